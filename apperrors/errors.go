@@ -0,0 +1,88 @@
+package apperrors
+
+import "errors"
+
+// Errores de dominio que devuelven los repositorios y servicios.
+// Se comparan con errors.Is y el servidor los traduce al código HTTP adecuado.
+var (
+	ErrNotFound     = errors.New("recurso no encontrado")
+	ErrForbidden    = errors.New("acceso denegado")
+	ErrConflict     = errors.New("conflicto con el estado actual del recurso")
+	ErrValidation   = errors.New("datos inválidos")
+	ErrUnauthorized = errors.New("no autenticado")
+)
+
+// Error es un error de dominio con un mensaje pensado para el cliente.
+// Kind es uno de los errores centinela de este paquete y Fields detalla,
+// en los errores de validación, qué campo falló.
+type Error struct {
+	Kind    error
+	Message string
+	Fields  []FieldError
+}
+
+// FieldError describe por qué no es válido un campo de la petición
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func NotFound(message string) error {
+	return &Error{Kind: ErrNotFound, Message: message}
+}
+
+func Forbidden(message string) error {
+	return &Error{Kind: ErrForbidden, Message: message}
+}
+
+func Conflict(message string) error {
+	return &Error{Kind: ErrConflict, Message: message}
+}
+
+func Validation(message string) error {
+	return &Error{Kind: ErrValidation, Message: message}
+}
+
+func Unauthorized(message string) error {
+	return &Error{Kind: ErrUnauthorized, Message: message}
+}
+
+// FieldErrors acumula errores por campo para informarlos todos juntos
+type FieldErrors []FieldError
+
+// Add registra el error del campo; si el campo ya tiene uno se conserva
+// el primero
+func (f *FieldErrors) Add(field, message string) {
+	for _, existing := range *f {
+		if existing.Field == field {
+			return
+		}
+	}
+	*f = append(*f, FieldError{Field: field, Message: message})
+}
+
+// Err devuelve un error de validación con los campos acumulados, o nil si
+// no hay ninguno
+func (f FieldErrors) Err() error {
+	if len(f) == 0 {
+		return nil
+	}
+	message := f[0].Message
+	if len(f) > 1 {
+		message = "hay campos inválidos"
+	}
+	return &Error{Kind: ErrValidation, Message: message, Fields: f}
+}
+
+// InvalidField es un error de validación de un único campo
+func InvalidField(field, message string) error {
+	return FieldErrors{{Field: field, Message: message}}.Err()
+}
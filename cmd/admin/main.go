@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/gopost-api/config"
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/repositories"
+	"github.com/gopost-api/services"
+)
+
+const usage = `Uso: admin <comando> [argumentos]
+
+Comandos:
+  set-role EMAIL ROL   Asigna el rol user, moderator o admin a un usuario
+
+Sirve para crear el primer administrador; después los roles se
+administran con PATCH /admin/users/{id}/role. Al cambiar el rol se cierran
+las sesiones del usuario, que debe volver a iniciar sesión.
+`
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := config.LoadConfig()
+	if err := database.Connect(cfg.DatabaseURL); err != nil {
+		log.Fatal("Error al conectar a la base de datos:", err)
+	}
+	defer database.Close()
+
+	// set-role solo cambia roles y revoca tokens: no firma tokens ni envía
+	// correos, así que el servicio no necesita claves, firmantes ni mailer
+	dialect := database.DialectFor(database.Driver)
+	users := repositories.NewUserRepository(database.DB, dialect)
+	tokens := services.NewTokenService(users, repositories.NewRefreshTokenRepository(database.DB, dialect),
+		repositories.NewRevocationRepository(database.DB, dialect), nil, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	userService := services.NewUserService(users, tokens, nil, nil, nil, nil, services.PasswordPolicy{}, services.UserSettings{})
+	if err := run(context.Background(), userService, args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// systemActor es quien actúa desde la CLI: un administrador sin cuenta
+// propia, que puede cambiar el rol de cualquier usuario
+var systemActor = services.Actor{Role: models.RoleAdmin}
+
+func run(ctx context.Context, userService *services.UserService, args []string) error {
+	switch args[0] {
+	case "set-role":
+		if len(args) != 3 {
+			return fmt.Errorf("set-role requiere el email y el rol")
+		}
+		role := models.Role(args[2])
+		if !role.Valid() {
+			return fmt.Errorf("rol inválido: %s (user, moderator o admin)", args[2])
+		}
+
+		user, err := userService.GetUserByEmail(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		// ChangeRole revoca los access y refresh tokens igual que la API. La
+		// API puede tardar hasta REVOCATION_CACHE_TTL en verlo.
+		if user, err = userService.ChangeRole(ctx, systemActor, user.ID, role); err != nil {
+			return err
+		}
+		fmt.Printf("✓ %s ahora tiene el rol %s\n", user.Email, role)
+
+	default:
+		return fmt.Errorf("comando desconocido: %s", args[0])
+	}
+	return nil
+}
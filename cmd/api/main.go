@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/gopost-api/config"
 	"github.com/gopost-api/database"
+	"github.com/gopost-api/database/migrations"
 	"github.com/gopost-api/handlers"
+	"github.com/gopost-api/mailer"
 	"github.com/gopost-api/middleware"
+	"github.com/gopost-api/models"
 	"github.com/gopost-api/repositories"
+	"github.com/gopost-api/repositories/memory"
 	"github.com/gopost-api/server"
 	"github.com/gopost-api/services"
 )
@@ -27,42 +32,154 @@ func main() {
 	if err := database.Connect(cfg.DatabaseURL); err != nil {
 		log.Fatal("Error al conectar a la base de datos:", err)
 	}
-	defer database.Close()
+
+	// Aplicar migraciones pendientes si está habilitado
+	if cfg.AutoMigrate {
+		migrator, err := migrations.New(database.DB, database.Driver)
+		if err != nil {
+			log.Fatal("Error al cargar las migraciones:", err)
+		}
+		count, err := migrator.Up(context.Background())
+		if err != nil {
+			log.Fatal("Error al aplicar las migraciones:", err)
+		}
+		log.Printf("✓ Migraciones aplicadas: %d", count)
+	}
 
 	// Inicializar repositorios
-	userRepo := repositories.NewUserRepository(database.DB)
-	postRepo := repositories.NewPostRepository(database.DB)
+	dialect := database.DialectFor(database.Driver)
+	userRepo := repositories.NewUserRepository(database.DB, dialect)
+	postRepo := repositories.NewPostRepository(database.DB, dialect)
+	refreshTokenRepo := repositories.NewRefreshTokenRepository(database.DB, dialect)
+	userTokenRepo := repositories.NewUserTokenRepository(database.DB, dialect)
+	accountRepo := repositories.NewAccountRepository(database.DB, dialect)
+
+	// Las revocaciones se consultan en cada petición autenticada: una caché
+	// en memoria evita ir a la base de datos cada vez
+	var revocationStore repositories.RevocationStore = repositories.NewRevocationRepository(database.DB, dialect)
+	if cfg.RevocationCacheTTL > 0 {
+		revocationStore = memory.NewRevocationCache(revocationStore, cfg.RevocationCacheTTL)
+	}
+
+	// Claves de firma de los access tokens
+	keys, err := services.LoadKeySet(cfg)
+	if err != nil {
+		log.Fatal("Error al cargar las claves JWT:", err)
+	}
+
+	// Secretos para firmar los cursores del feed y los tokens por email
+	cursorSigner, err := services.NewSigner("CURSOR_SECRET", cfg.CursorSecret, cfg.JWTSecret)
+	if err != nil {
+		log.Fatal("Error en el secreto de los cursores:", err)
+	}
+	userTokenSigner, err := services.NewSigner("USER_TOKEN_SECRET", cfg.UserTokenSecret, cfg.JWTSecret, cfg.CursorSecret)
+	if err != nil {
+		log.Fatal("Error en el secreto de los tokens por email:", err)
+	}
+
+	// Envío de correos (verificación y cambio de email, restablecimiento de contraseña)
+	mail, err := mailer.New(cfg)
+	if err != nil {
+		log.Fatal("Error al configurar el envío de correos:", err)
+	}
+
+	if !repositories.PostsOnDelete(cfg.AccountDeletionMode).Valid() {
+		log.Fatalf("ACCOUNT_DELETION_MODE inválido: %q (delete o anonymize)", cfg.AccountDeletionMode)
+	}
 
 	// Inicializar servicios
-	userService := services.NewUserService(userRepo)
-	postService := services.NewPostService(postRepo)
+	tokenService := services.NewTokenService(userRepo, refreshTokenRepo, revocationStore, keys, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	userService := services.NewUserService(userRepo, tokenService, userTokenRepo, userTokenSigner, accountRepo, mail,
+		services.NewPasswordPolicy(cfg), services.NewUserSettings(cfg))
+	postService := services.NewPostService(postRepo, userRepo, cursorSigner, cfg.RequireEmailVerification)
 
 	// Inicializar handlers
 	userHandler := handlers.NewUserHandler(userService)
 	postHandler := handlers.NewPostHandler(postService)
+	jwksHandler := handlers.NewJWKSHandler(tokenService)
+	adminHandler := handlers.NewAdminHandler(userService)
 
 	// Crear aplicación
-	app := server.New()
+	app := server.New(server.WithConfig(cfg))
+
+	// Cerrar la base de datos al apagar el servidor
+	app.OnShutdown(func(ctx context.Context) error {
+		return database.Close()
+	})
+
+	// Purga periódica de las cuentas cuyo período de gracia venció y de las
+	// revocaciones de tokens ya expirados. Se detiene antes de cerrar la
+	// base de datos.
+	purgeCtx, stopPurger := context.WithCancel(context.Background())
+	purgerDone := make(chan struct{})
+	go func() {
+		defer close(purgerDone)
+		userService.RunAccountPurger(purgeCtx, cfg.AccountPurgeInterval)
+	}()
+	// Los correos en segundo plano también usan la base de datos
+	app.OnShutdown(userService.Wait)
+	app.OnShutdown(func(ctx context.Context) error {
+		stopPurger()
+		select {
+		case <-purgerDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	// Middlewares globales
+	app.Use(middleware.LoggerMiddleware, middleware.RecoveryMiddleware)
+	// El timeout de cada petición es menor que el WriteTimeout del servidor
+	// para que el cliente reciba el 503 antes de que se corte la conexión
+	if cfg.RequestTimeout > 0 {
+		app.Use(middleware.TimeoutMiddleware(cfg.RequestTimeout))
+	}
+	requireAuth := middleware.AuthMiddleware(tokenService)
 
 	// Ruta de bienvenida
 	app.Get("/health", health)
 
-	// Rutas públicas - Usuarios
-	app.Post("/auth/signup", userHandler.SignUpHandler)
-	app.Post("/auth/login", userHandler.LoginHandler)
+	// Claves públicas para verificar los access tokens
+	app.Get("/.well-known/jwks.json", server.Handler(jwksHandler.JWKSHandler))
+
+	// Rutas de autenticación
+	auth := app.Group("/auth")
+	auth.Post("/signup", server.Handler(userHandler.SignUpHandler))
+	auth.Post("/login", server.Handler(userHandler.LoginHandler))
+	auth.Post("/refresh", server.Handler(userHandler.RefreshHandler))
+	auth.Post("/verify", server.Handler(userHandler.VerifyEmailHandler))
+	auth.Post("/resend-verification", server.Handler(userHandler.ResendVerificationHandler))
+	auth.Post("/password/forgot", server.Handler(userHandler.ForgotPasswordHandler))
+	auth.Post("/password/reset", server.Handler(userHandler.ResetPasswordHandler))
+	auth.Post("/email/confirm", server.Handler(userHandler.ConfirmEmailChangeHandler))
 
 	// Rutas protegidas - Usuarios
-	app.Get("/auth/me", middleware.AuthMiddleware(userHandler.MeHandler))
+	authPrivate := auth.Group("", requireAuth)
+	authPrivate.Get("/me", server.Handler(userHandler.MeHandler))
+	authPrivate.Patch("/me", server.Handler(userHandler.UpdateMeHandler))
+	authPrivate.Delete("/me", server.Handler(userHandler.DeleteMeHandler))
+	authPrivate.Post("/me/password", server.Handler(userHandler.ChangePasswordHandler))
+	authPrivate.Post("/me/email", server.Handler(userHandler.RequestEmailChangeHandler))
+	authPrivate.Post("/logout", server.Handler(userHandler.LogoutHandler))
+	authPrivate.Post("/logout-all", server.Handler(userHandler.LogoutAllHandler))
 
 	// Rutas públicas - Posts
-	app.Get("/posts", postHandler.GetPostsHandler)
-	app.Get("/posts/{id}", postHandler.GetPostHandler)
+	posts := app.Group("/posts")
+	posts.Get("", server.Handler(postHandler.GetPostsHandler))
+	posts.Get("/search", server.Handler(postHandler.SearchPostsHandler))
+	posts.Get("/{id}", server.Handler(postHandler.GetPostHandler))
 
 	// Rutas protegidas - Posts
-	app.Post("/posts", middleware.AuthMiddleware(postHandler.CreatePostHandler))
-	app.Put("/posts/{id}", middleware.AuthMiddleware(postHandler.UpdatePostHandler))
-	app.Delete("/posts/{id}", middleware.AuthMiddleware(postHandler.DeletePostHandler))
-	app.Get("/posts/me", middleware.AuthMiddleware(postHandler.GetPostMeHandler))
+	postsPrivate := posts.Group("", requireAuth)
+	postsPrivate.Post("", server.Handler(postHandler.CreatePostHandler))
+	postsPrivate.Put("/{id}", server.Handler(postHandler.UpdatePostHandler))
+	postsPrivate.Delete("/{id}", server.Handler(postHandler.DeletePostHandler))
+	postsPrivate.Get("/me", server.Handler(postHandler.GetPostMeHandler))
+
+	// Rutas de administración
+	admin := app.Group("/admin", requireAuth, middleware.RequireRole(models.RoleAdmin))
+	admin.Patch("/users/{id}/role", server.Handler(adminHandler.UpdateUserRoleHandler))
 
 	// Iniciar servidor
 	if err := app.RunServer(); err != nil {
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/gopost-api/config"
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/database/migrations"
+)
+
+const usage = `Uso: migrate <comando> [argumentos]
+
+Comandos:
+  up            Aplica todas las migraciones pendientes
+  down          Revierte la última migración aplicada
+  status        Muestra el estado de cada migración
+  goto N        Sube o baja el esquema hasta la versión N (0 revierte todo)
+  create NAME   Crea los archivos up/down de una nueva migración
+
+Opciones:
+`
+
+func main() {
+	dir := flag.String("dir", migrations.SourceDir, "directorio donde create genera los archivos SQL")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// create no necesita conexión a la base de datos
+	if args[0] == "create" {
+		if len(args) != 2 {
+			log.Fatal("create requiere el nombre de la migración")
+		}
+		paths, err := migrations.Create(*dir, args[1])
+		if err != nil {
+			log.Fatal("Error al crear la migración:", err)
+		}
+		for _, path := range paths {
+			fmt.Println("Creado", path)
+		}
+		return
+	}
+
+	cfg := config.LoadConfig()
+	if err := database.Connect(cfg.DatabaseURL); err != nil {
+		log.Fatal("Error al conectar a la base de datos:", err)
+	}
+	defer database.Close()
+
+	migrator, err := migrations.New(database.DB, database.Driver)
+	if err != nil {
+		log.Fatal("Error al cargar las migraciones:", err)
+	}
+
+	if err := run(context.Background(), migrator, args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, migrator *migrations.Migrator, args []string) error {
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ %d migraciones aplicadas\n", count)
+
+	case "down":
+		if err := migrator.Down(ctx); err != nil {
+			return err
+		}
+		fmt.Println("✓ Última migración revertida")
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pendiente"
+			if status.Applied {
+				state = "aplicada " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-30s %s\n", status.Version, status.Name, state)
+		}
+
+	case "goto":
+		if len(args) != 2 {
+			return fmt.Errorf("goto requiere la versión de destino")
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("versión inválida: %s", args[1])
+		}
+		if err := migrator.Goto(ctx, version); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Esquema en la versión %d\n", version)
+
+	default:
+		return fmt.Errorf("comando desconocido: %s", args[0])
+	}
+	return nil
+}
@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -11,6 +14,75 @@ type Config struct {
 	Port        string
 	JWTSecret   string
 	DatabaseURL string
+	AutoMigrate bool
+
+	// Firma de los access tokens. Con HS256 se usa JWTSecret; con RS256,
+	// ES256 o EdDSA la clave privada del archivo PEM indicado.
+	JWTAlgorithm      string
+	JWTPrivateKeyFile string
+	JWTKeyID          string
+	// Claves públicas (PEM) que se siguen aceptando durante una rotación,
+	// opcionalmente con su kid: "kid=ruta" o solo "ruta"
+	JWTVerifyKeyFiles []string
+
+	// Duración de los tokens de autenticación
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// Secretos HMAC propios de cada uso, distintos de JWT_SECRET: los
+	// cursores de paginación y los tokens que se envían por email
+	CursorSecret    string
+	UserTokenSecret string
+
+	// Tiempo que se recuerda en memoria una consulta de revocación; 0 la
+	// desactiva y consulta la base de datos en cada petición
+	RevocationCacheTTL time.Duration
+
+	// URL pública de la aplicación, usada en los enlaces de los correos
+	AppURL string
+
+	// Verificación de email
+	RequireEmailVerification bool
+	EmailVerificationTTL     time.Duration
+
+	// Vigencia de los enlaces para restablecer la contraseña
+	PasswordResetTTL time.Duration
+
+	// Política de contraseñas. El largo se cuenta en caracteres; las
+	// clases (minúsculas, mayúsculas, dígitos y símbolos) son opcionales.
+	PasswordMinLength     int
+	PasswordRequireLower  bool
+	PasswordRequireUpper  bool
+	PasswordRequireDigit  bool
+	PasswordRequireSymbol bool
+	PasswordRejectCommon  bool
+
+	// Eliminación de cuentas: ACCOUNT_DELETION_MODE es delete (borra los
+	// posts) o anonymize (los reasigna a "Usuario eliminado"). La cuenta se
+	// purga cuando pasa el período de gracia; 0 la borra de inmediato. Cada
+	// AccountPurgeInterval también se borran las revocaciones expiradas.
+	AccountDeletionMode  string
+	AccountDeletionGrace time.Duration
+	AccountPurgeInterval time.Duration
+
+	// Envío de correos: MAILER es log, file o smtp y es obligatorio
+	Mailer       string
+	MailFrom     string
+	MailDir      string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	// Ajustes del servidor HTTP
+	// RequestTimeout es el tiempo máximo que puede tardar un handler; 0
+	// desactiva el TimeoutMiddleware
+	RequestTimeout  time.Duration
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	MaxHeaderBytes  int
+	ShutdownTimeout time.Duration
 }
 
 var AppConfig *Config
@@ -22,8 +94,55 @@ func LoadConfig() *Config {
 
 	AppConfig = &Config{
 		Port:        getEnv("PORT", ":8080"),
-		JWTSecret:   getEnv("JWT_SECRET", "default_secret_key"),
+		JWTSecret:   getEnv("JWT_SECRET", ""),
 		DatabaseURL: getEnv("DATABASE_URL", ""),
+		AutoMigrate: getEnvBool("AUTO_MIGRATE", false),
+
+		JWTAlgorithm:      getEnv("JWT_ALGORITHM", "HS256"),
+		JWTPrivateKeyFile: getEnv("JWT_PRIVATE_KEY_FILE", ""),
+		JWTKeyID:          getEnv("JWT_KEY_ID", ""),
+		JWTVerifyKeyFiles: getEnvList("JWT_VERIFY_KEY_FILES"),
+
+		AccessTokenTTL:  getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		CursorSecret:    getEnv("CURSOR_SECRET", ""),
+		UserTokenSecret: getEnv("USER_TOKEN_SECRET", ""),
+
+		RevocationCacheTTL: getEnvDuration("REVOCATION_CACHE_TTL", 10*time.Second),
+
+		AppURL: getEnv("APP_URL", "http://localhost:8080"),
+
+		RequireEmailVerification: getEnvBool("REQUIRE_EMAIL_VERIFICATION", true),
+		EmailVerificationTTL:     getEnvDuration("EMAIL_VERIFICATION_TTL", 24*time.Hour),
+
+		PasswordResetTTL: getEnvDuration("PASSWORD_RESET_TTL", time.Hour),
+
+		PasswordMinLength:     getEnvInt("PASSWORD_MIN_LENGTH", 8),
+		PasswordRequireLower:  getEnvBool("PASSWORD_REQUIRE_LOWER", false),
+		PasswordRequireUpper:  getEnvBool("PASSWORD_REQUIRE_UPPER", false),
+		PasswordRequireDigit:  getEnvBool("PASSWORD_REQUIRE_DIGIT", false),
+		PasswordRequireSymbol: getEnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+		PasswordRejectCommon:  getEnvBool("PASSWORD_REJECT_COMMON", true),
+
+		AccountDeletionMode:  getEnv("ACCOUNT_DELETION_MODE", "anonymize"),
+		AccountDeletionGrace: getEnvDuration("ACCOUNT_DELETION_GRACE", 30*24*time.Hour),
+		AccountPurgeInterval: getEnvDuration("ACCOUNT_PURGE_INTERVAL", time.Hour),
+
+		Mailer:       getEnv("MAILER", ""),
+		MailFrom:     getEnv("MAIL_FROM", "GoPost <no-reply@gopost.local>"),
+		MailDir:      getEnv("MAIL_DIR", "mail"),
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnvInt("SMTP_PORT", 587),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+
+		RequestTimeout:  getEnvDuration("REQUEST_TIMEOUT", 10*time.Second),
+		ReadTimeout:     getEnvDuration("READ_TIMEOUT", 15*time.Second),
+		WriteTimeout:    getEnvDuration("WRITE_TIMEOUT", 15*time.Second),
+		IdleTimeout:     getEnvDuration("IDLE_TIMEOUT", 60*time.Second),
+		MaxHeaderBytes:  getEnvInt("MAX_HEADER_BYTES", 1<<20),
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
 	}
 
 	return AppConfig
@@ -36,3 +155,54 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// getEnvList lee una lista separada por comas, ignorando los elementos vacíos
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvDuration lee una duración con el formato de time.ParseDuration, por ejemplo "15s"
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Valor inválido para %s (%q), usando %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Valor inválido para %s (%q), usando %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return b
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Valor inválido para %s (%q), usando %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Querier es lo que comparten *sql.DB y *sql.Tx, para que las consultas
+// funcionen igual dentro y fuera de una transacción
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Dialect encapsula las diferencias de SQL entre motores. Las consultas se
+// escriben con placeholders "?" (estilo MySQL) y el dialecto las adapta.
+type Dialect interface {
+	// Name devuelve el nombre del driver
+	Name() string
+	// Rebind adapta los placeholders "?" al formato del motor
+	Rebind(query string) string
+	// InsertID ejecuta un INSERT y devuelve el id generado
+	InsertID(ctx context.Context, q Querier, query string, args ...interface{}) (int64, error)
+	// TimeValue convierte un instante al argumento que el motor compara
+	// correctamente con sus columnas de fecha (siempre en UTC)
+	TimeValue(t time.Time) interface{}
+}
+
+// sqliteTimeLayout es el formato de CURRENT_TIMESTAMP en SQLite
+const sqliteTimeLayout = "2006-01-02 15:04:05"
+
+// Now devuelve el instante actual en UTC truncado a segundos, la precisión
+// común de las columnas TIMESTAMP de los tres motores
+func Now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// DialectFor devuelve el dialecto del driver; si no lo conoce usa el de MySQL
+func DialectFor(driver string) Dialect {
+	switch driver {
+	case DriverPostgres:
+		return postgresDialect{}
+	case DriverSQLite:
+		return lastInsertIDDialect{name: DriverSQLite}
+	default:
+		return lastInsertIDDialect{name: DriverMySQL}
+	}
+}
+
+// lastInsertIDDialect cubre MySQL y SQLite: placeholders "?" y LastInsertId
+type lastInsertIDDialect struct {
+	name string
+}
+
+func (d lastInsertIDDialect) Name() string {
+	return d.name
+}
+
+func (d lastInsertIDDialect) Rebind(query string) string {
+	return query
+}
+
+func (d lastInsertIDDialect) InsertID(ctx context.Context, q Querier, query string, args ...interface{}) (int64, error) {
+	result, err := q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (d lastInsertIDDialect) TimeValue(t time.Time) interface{} {
+	// SQLite guarda las fechas como texto: se compara con su mismo formato
+	if d.name == DriverSQLite {
+		return t.UTC().Format(sqliteTimeLayout)
+	}
+	return t.UTC()
+}
+
+// postgresDialect usa placeholders $1, $2... y RETURNING id, porque
+// PostgreSQL no implementa LastInsertId
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string {
+	return DriverPostgres
+}
+
+func (postgresDialect) Rebind(query string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+
+	n := 0
+	inString := false
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == '\'':
+			inString = !inString
+			b.WriteByte(ch)
+		case ch == '?' && !inString:
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+func (d postgresDialect) InsertID(ctx context.Context, q Querier, query string, args ...interface{}) (int64, error) {
+	var id int64
+	err := q.QueryRowContext(ctx, d.Rebind(query)+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+func (postgresDialect) TimeValue(t time.Time) interface{} {
+	return t.UTC()
+}
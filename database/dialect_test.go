@@ -0,0 +1,93 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPostgresRebind(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "sin placeholders",
+			query: "SELECT 1",
+			want:  "SELECT 1",
+		},
+		{
+			name:  "numera en orden",
+			query: "UPDATE users SET name = ?, bio = ? WHERE id = ?",
+			want:  "UPDATE users SET name = $1, bio = $2 WHERE id = $3",
+		},
+		{
+			name:  "ignora ? dentro de cadenas",
+			query: "SELECT id FROM posts WHERE title = '¿qué?' AND user_id = ?",
+			want:  "SELECT id FROM posts WHERE title = '¿qué?' AND user_id = $1",
+		},
+		{
+			name:  "comillas escapadas duplicándolas",
+			query: "SELECT 'it''s ?' , ?",
+			want:  "SELECT 'it''s ?' , $1",
+		},
+		{
+			name:  "LIKE con ESCAPE de barra invertida",
+			query: `SELECT id FROM posts WHERE LOWER(title) LIKE ? ESCAPE '\' OR LOWER(content) LIKE ? ESCAPE '\'`,
+			want:  `SELECT id FROM posts WHERE LOWER(title) LIKE $1 ESCAPE '\' OR LOWER(content) LIKE $2 ESCAPE '\'`,
+		},
+		{
+			name:  "más de nueve placeholders",
+			query: "VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			want:  "VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (postgresDialect{}).Rebind(tt.query); got != tt.want {
+				t.Errorf("Rebind() = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialectFor(t *testing.T) {
+	query := "SELECT ? , ?"
+	tests := []struct {
+		driver     string
+		wantName   string
+		wantRebind string
+	}{
+		{driver: DriverMySQL, wantName: DriverMySQL, wantRebind: query},
+		{driver: DriverSQLite, wantName: DriverSQLite, wantRebind: query},
+		{driver: DriverPostgres, wantName: DriverPostgres, wantRebind: "SELECT $1 , $2"},
+		{driver: "desconocido", wantName: DriverMySQL, wantRebind: query},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			d := DialectFor(tt.driver)
+			if d.Name() != tt.wantName {
+				t.Errorf("Name() = %q, se esperaba %q", d.Name(), tt.wantName)
+			}
+			if got := d.Rebind(query); got != tt.wantRebind {
+				t.Errorf("Rebind() = %q, se esperaba %q", got, tt.wantRebind)
+			}
+		})
+	}
+}
+
+func TestTimeValue(t *testing.T) {
+	local := time.Date(2024, 3, 1, 9, 30, 15, 0, time.FixedZone("UTC-3", -3*3600))
+
+	if got := DialectFor(DriverSQLite).TimeValue(local); got != "2024-03-01 12:30:15" {
+		t.Errorf("SQLite TimeValue() = %v, se esperaba el texto en UTC", got)
+	}
+	for _, driver := range []string{DriverMySQL, DriverPostgres} {
+		got, ok := DialectFor(driver).TimeValue(local).(time.Time)
+		if !ok || got.Location() != time.UTC || !got.Equal(local) {
+			t.Errorf("%s TimeValue() = %v, se esperaba el mismo instante en UTC", driver, got)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// Create genera el par de archivos vacíos de la siguiente versión en el
+// directorio de cada dialecto dentro de dir, para que todos compartan la
+// misma numeración, y devuelve sus rutas. Quedan embebidos al recompilar.
+func Create(dir, name string) ([]string, error) {
+	name = strings.ReplaceAll(strings.TrimSpace(name), " ", "_")
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("nombre de migración inválido: %q (usa letras, números y _)", name)
+	}
+
+	dialects := make([]string, 0, len(dialectDirs))
+	for _, dialect := range dialectDirs {
+		dialects = append(dialects, dialect)
+	}
+	sort.Strings(dialects)
+
+	// La siguiente versión es la mayor de todos los dialectos más uno
+	var next int64 = 1
+	for _, dialect := range dialects {
+		existing, err := load(os.DirFS(filepath.Join(dir, dialect)), ".")
+		if err != nil {
+			return nil, err
+		}
+		if n := len(existing); n > 0 && existing[n-1].Version >= next {
+			next = existing[n-1].Version + 1
+		}
+	}
+
+	var paths []string
+	for _, dialect := range dialects {
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, dialect, fmt.Sprintf("%04d_%s.%s.sql", next, name, direction))
+			content := fmt.Sprintf("-- %04d_%s (%s, %s)\n", next, name, direction, dialect)
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				return nil, fmt.Errorf("error al crear %s: %w", path, err)
+			}
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,391 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gopost-api/database"
+)
+
+// files contiene un directorio por dialecto con los archivos
+// NNNN_nombre.up.sql / NNNN_nombre.down.sql
+//
+//go:embed sql/*/*.sql
+var files embed.FS
+
+// SourceDir es el directorio, relativo a la raíz del proyecto, donde viven
+// los archivos SQL embebidos. Lo usa el comando create para generar nuevos.
+const SourceDir = "database/migrations/sql"
+
+// dialectDirs asocia cada driver con su directorio de migraciones
+var dialectDirs = map[string]string{
+	database.DriverMySQL:    "mysql",
+	database.DriverSQLite:   "sqlite",
+	database.DriverPostgres: "postgres",
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-zA-Z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration es una versión del esquema con su SQL de subida y de bajada
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status describe si una migración ya fue aplicada
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Migrator aplica y revierte las migraciones embebidas, registrando las
+// versiones aplicadas en la tabla schema_migrations.
+//
+// Cada migración corre en una transacción, pero MySQL confirma de forma
+// implícita cada sentencia DDL (CREATE, ALTER, DROP...). Allí una migración
+// que falla a la mitad deja aplicadas las sentencias anteriores sin
+// registrar la versión: hay que revertirlas a mano antes de reintentar.
+// Por eso las migraciones de MySQL tienen una sola sentencia DDL, al
+// final, o las anteriores son idempotentes: IF NOT EXISTS o, donde MySQL
+// no lo admite, un ALTER preparado que solo se ejecuta si information_schema
+// indica que falta (ver 0008).
+type Migrator struct {
+	db         *sql.DB
+	dialect    database.Dialect
+	migrations []Migration
+}
+
+// New carga las migraciones embebidas para el driver indicado
+func New(db *sql.DB, driver string) (*Migrator, error) {
+	dir, ok := dialectDirs[driver]
+	if !ok {
+		return nil, fmt.Errorf("no hay migraciones para el driver %q", driver)
+	}
+
+	migrations, err := load(files, path.Join("sql", dir))
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: database.DialectFor(driver), migrations: migrations}, nil
+}
+
+// load lee y ordena las migraciones de un directorio; cada versión debe
+// tener su archivo .up.sql y su archivo .down.sql
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer las migraciones: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error al leer %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("la versión %d tiene nombres distintos: %s y %s", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("la migración %04d_%s debe tener archivos up y down", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up aplica todas las migraciones pendientes y devuelve cuántas aplicó
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if err := m.apply(ctx, migration, true); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Down revierte la última migración aplicada
+func (m *Migrator) Down(ctx context.Context) error {
+	current, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if current == 0 {
+		return fmt.Errorf("no hay migraciones aplicadas")
+	}
+
+	migration, ok := m.find(current)
+	if !ok {
+		return fmt.Errorf("la versión aplicada %d no existe en los archivos de migración", current)
+	}
+	return m.apply(ctx, migration, false)
+}
+
+// Goto sube o baja el esquema hasta dejarlo exactamente en la versión
+// indicada. La versión 0 revierte todas las migraciones.
+func (m *Migrator) Goto(ctx context.Context, version int64) error {
+	if _, ok := m.find(version); !ok && version != 0 {
+		return fmt.Errorf("la versión %d no existe", version)
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Revertir de la más nueva a la más antigua las que superan la versión
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; ok && migration.Version > version {
+			if err := m.apply(ctx, migration, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Aplicar en orden las pendientes hasta la versión
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok && migration.Version <= version {
+			if err := m.apply(ctx, migration, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Version devuelve la versión aplicada más alta, o 0 si no hay ninguna
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var current int64
+	for version := range applied {
+		if version > current {
+			current = version
+		}
+	}
+	return current, nil
+}
+
+// Status lista todas las migraciones conocidas indicando si están aplicadas
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+// ensureTable crea la tabla de control si todavía no existe
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	query := `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+	if _, err := m.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("error al crear schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// applied devuelve las versiones aplicadas junto con su fecha de aplicación
+func (m *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error al leer schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var appliedAt interface{}
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("error al escanear schema_migrations: %w", err)
+		}
+		applied[version] = parseTimestamp(appliedAt)
+	}
+	return applied, rows.Err()
+}
+
+// parseTimestamp interpreta applied_at tal como lo entregue el driver:
+// time.Time o texto, según la configuración de la conexión
+func parseTimestamp(value interface{}) time.Time {
+	var text string
+	switch v := value.(type) {
+	case time.Time:
+		return v
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return time.Time{}
+	}
+
+	for _, layout := range []string{time.DateTime, time.RFC3339Nano} {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// apply ejecuta el SQL de subida o de bajada de una migración y actualiza
+// schema_migrations dentro de la misma transacción. En MySQL el DDL no
+// se revierte con la transacción; ver Migrator.
+func (m *Migrator) apply(ctx context.Context, migration Migration, up bool) error {
+	script, direction := migration.Down, "down"
+	if up {
+		script, direction = migration.Up, "up"
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, statement := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			if m.dialect.Name() == database.DriverMySQL {
+				return fmt.Errorf("error en la migración %04d_%s (%s), las sentencias DDL anteriores quedaron aplicadas: %w", migration.Version, migration.Name, direction, err)
+			}
+			return fmt.Errorf("error en la migración %04d_%s (%s): %w", migration.Version, migration.Name, direction, err)
+		}
+	}
+
+	if up {
+		_, err = tx.ExecContext(ctx, m.dialect.Rebind("INSERT INTO schema_migrations (version, name) VALUES (?, ?)"), migration.Version, migration.Name)
+	} else {
+		_, err = tx.ExecContext(ctx, m.dialect.Rebind("DELETE FROM schema_migrations WHERE version = ?"), migration.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("error al registrar la migración %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	return tx.Commit()
+}
+
+// splitStatements separa un script en sentencias terminadas en ";" al final
+// de línea. Las sentencias que contienen ";" internos (por ejemplo triggers)
+// se encierran entre las líneas "-- +StatementBegin" y "-- +StatementEnd".
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	inBlock := false
+
+	flush := func() {
+		statement := strings.TrimSpace(current.String())
+		statement = strings.TrimSuffix(statement, ";")
+		if strings.TrimSpace(statement) != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "-- +StatementBegin":
+			flush()
+			inBlock = true
+			continue
+		case trimmed == "-- +StatementEnd":
+			inBlock = false
+			// Dentro del bloque el ";" final forma parte de la sentencia
+			statement := strings.TrimSpace(current.String())
+			if statement != "" {
+				statements = append(statements, statement)
+			}
+			current.Reset()
+			continue
+		case strings.HasPrefix(trimmed, "--") && !inBlock:
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+	flush()
+
+	return statements
+}
@@ -0,0 +1,288 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"reflect"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gopost-api/database"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "sentencias separadas por ; al final de línea",
+			script: "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n",
+			want:   []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:   "una sentencia en varias líneas",
+			script: "ALTER TABLE users\n    ADD COLUMN bio TEXT,\n    ADD COLUMN url TEXT;\n",
+			want:   []string{"ALTER TABLE users\n    ADD COLUMN bio TEXT,\n    ADD COLUMN url TEXT"},
+		},
+		{
+			name:   "los comentarios se ignoran",
+			script: "-- comentario\nDROP TABLE a;\n-- otro\n",
+			want:   []string{"DROP TABLE a"},
+		},
+		{
+			name:   "un script solo con comentarios no tiene sentencias",
+			script: "-- Irreversible\n",
+			want:   nil,
+		},
+		{
+			name:   "un ; dentro de la línea no corta la sentencia",
+			script: "INSERT INTO t VALUES ('a;b');\n",
+			want:   []string{"INSERT INTO t VALUES ('a;b')"},
+		},
+		{
+			name:   "la última sentencia puede no terminar en ;",
+			script: "DROP TABLE a;\nDROP TABLE b",
+			want:   []string{"DROP TABLE a", "DROP TABLE b"},
+		},
+		{
+			name:   "bloques con ; internos",
+			script: "-- +StatementBegin\nCREATE TRIGGER t AFTER INSERT ON a BEGIN\n    UPDATE b SET n = n + 1;\nEND;\n-- +StatementEnd\nDROP TABLE c;\n",
+			want: []string{
+				"CREATE TRIGGER t AFTER INSERT ON a BEGIN\n    UPDATE b SET n = n + 1;\nEND;",
+				"DROP TABLE c",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements() = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadRequiresUpAndDown(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/0001_a.up.sql":   {Data: []byte("CREATE TABLE a (id INT);")},
+		"sql/0001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"sql/0002_b.up.sql":   {Data: []byte("CREATE TABLE b (id INT);")},
+	}
+	if _, err := load(fsys, "sql"); err == nil {
+		t.Fatal("se esperaba un error por la migración sin archivo down")
+	}
+}
+
+// openSQLite abre una base SQLite en memoria con una sola conexión, para
+// que todas las consultas vean la misma base
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	driver, dsn, err := database.ParseURL(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count > 0
+}
+
+func TestMigratorUpDownGoto(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	m := &Migrator{
+		db:      db,
+		dialect: database.DialectFor(database.DriverSQLite),
+		migrations: []Migration{
+			{Version: 1, Name: "a", Up: "CREATE TABLE a (id INTEGER);", Down: "DROP TABLE a;"},
+			{Version: 2, Name: "b", Up: "CREATE TABLE b (id INTEGER);", Down: "DROP TABLE b;"},
+			{Version: 3, Name: "c", Up: "CREATE TABLE c (id INTEGER);", Down: "DROP TABLE c;"},
+		},
+	}
+
+	steps := []struct {
+		name        string
+		run         func() error
+		wantVersion int64
+		wantTables  map[string]bool
+	}{
+		{
+			name:        "Up aplica todas",
+			run:         func() error { _, err := m.Up(ctx); return err },
+			wantVersion: 3,
+			wantTables:  map[string]bool{"a": true, "b": true, "c": true},
+		},
+		{
+			name:        "Down revierte la última",
+			run:         func() error { return m.Down(ctx) },
+			wantVersion: 2,
+			wantTables:  map[string]bool{"a": true, "b": true, "c": false},
+		},
+		{
+			name:        "Goto baja hasta la versión",
+			run:         func() error { return m.Goto(ctx, 1) },
+			wantVersion: 1,
+			wantTables:  map[string]bool{"a": true, "b": false, "c": false},
+		},
+		{
+			name:        "Goto sube hasta la versión",
+			run:         func() error { return m.Goto(ctx, 3) },
+			wantVersion: 3,
+			wantTables:  map[string]bool{"a": true, "b": true, "c": true},
+		},
+		{
+			name:        "Goto 0 revierte todo",
+			run:         func() error { return m.Goto(ctx, 0) },
+			wantVersion: 0,
+			wantTables:  map[string]bool{"a": false, "b": false, "c": false},
+		},
+	}
+
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		version, err := m.Version(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version != step.wantVersion {
+			t.Errorf("%s: versión %d, se esperaba %d", step.name, version, step.wantVersion)
+		}
+		for table, want := range step.wantTables {
+			if got := tableExists(t, db, table); got != want {
+				t.Errorf("%s: tabla %s existe = %v, se esperaba %v", step.name, table, got, want)
+			}
+		}
+	}
+
+	if err := m.Down(ctx); err == nil {
+		t.Error("Down sin migraciones aplicadas debía fallar")
+	}
+	if err := m.Goto(ctx, 9); err == nil {
+		t.Error("Goto a una versión inexistente debía fallar")
+	}
+}
+
+func TestMigratorFailedMigrationIsRolledBack(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	m := &Migrator{
+		db:      db,
+		dialect: database.DialectFor(database.DriverSQLite),
+		migrations: []Migration{
+			{Version: 1, Name: "roto", Up: "CREATE TABLE a (id INTEGER);\nNO ES SQL;", Down: "DROP TABLE a;"},
+		},
+	}
+
+	if _, err := m.Up(ctx); err == nil {
+		t.Fatal("se esperaba el error de la sentencia inválida")
+	}
+	if tableExists(t, db, "a") {
+		t.Error("la tabla de una migración fallida no debía quedar creada")
+	}
+	if version, _ := m.Version(ctx); version != 0 {
+		t.Errorf("versión %d, la migración fallida no debía registrarse", version)
+	}
+}
+
+// Las migraciones embebidas de SQLite deben poder aplicarse y revertirse
+// completas
+func TestEmbeddedSQLiteMigrations(t *testing.T) {
+	ctx := context.Background()
+	m, err := New(openSQLite(t), database.DriverSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if err := m.Goto(ctx, 0); err != nil {
+		t.Fatalf("Goto 0: %v", err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up tras revertir: %v", err)
+	}
+}
+
+// En MySQL cada DDL se confirma por separado: una DDL que no es la última
+// sentencia de la migración debe poder repetirse si una posterior falla
+func TestEmbeddedMySQLMigrationsRerunnable(t *testing.T) {
+	ddl := regexp.MustCompile(`(?i)^(CREATE|ALTER|DROP|RENAME|TRUNCATE)\b`)
+	guarded := regexp.MustCompile(`(?i)\bIF (NOT )?EXISTS\b`)
+
+	migrations, err := load(files, path.Join("sql", dialectDirs[database.DriverMySQL]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range migrations {
+		for direction, script := range map[string]string{"up": m.Up, "down": m.Down} {
+			statements := splitStatements(script)
+			for i, statement := range statements[:max(len(statements)-1, 0)] {
+				if ddl.MatchString(statement) && !guarded.MatchString(statement) {
+					t.Errorf("%04d_%s (%s): la sentencia %d no es idempotente y no es la última:\n%s", m.Version, m.Name, direction, i+1, statement)
+				}
+			}
+		}
+	}
+}
+
+// 0011 pasa los emails a minúsculas aunque la columna los compare sin
+// distinguir mayúsculas
+func TestEmbeddedSQLiteEmailLowercase(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	m, err := New(db, database.DriverSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Goto(ctx, 10); err != nil {
+		t.Fatalf("Goto 10: %v", err)
+	}
+
+	emails := map[string]string{
+		"  Ana@Example.COM": "ana@example.com",
+		"Bea@Example.COM":   "bea@example.com",
+		"carla@example.com": "carla@example.com",
+	}
+	for email := range emails {
+		if _, err := db.Exec("INSERT INTO users (name, email, password) VALUES ('Test', ?, 'x')", email); err != nil {
+			t.Fatalf("INSERT %q: %v", email, err)
+		}
+	}
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	for original, want := range emails {
+		var got string
+		// LIKE con el email esperado encuentra la fila sin importar cómo
+		// quedó escrita; el valor leído se compara exacto
+		if err := db.QueryRow("SELECT email FROM users WHERE TRIM(email) LIKE ?", want).Scan(&got); err != nil {
+			t.Fatalf("SELECT %q: %v", want, err)
+		}
+		if got != want {
+			t.Errorf("email %q quedó como %q, se esperaba %q", original, got, want)
+		}
+	}
+}
@@ -0,0 +1 @@
+ALTER TABLE posts ADD FULLTEXT INDEX ft_posts_title_content (title, content);
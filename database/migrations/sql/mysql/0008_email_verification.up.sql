@@ -0,0 +1,34 @@
+-- MySQL confirma cada DDL por separado, así que todo lo anterior al UPDATE
+-- debe poder repetirse si la migración falla a la mitad: la tabla usa IF
+-- NOT EXISTS y la columna se agrega solo si todavía no existe.
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id INT UNSIGNED NOT NULL,
+    purpose VARCHAR(32) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL DEFAULT NULL,
+    UNIQUE KEY uq_user_tokens_hash (token_hash),
+    KEY idx_user_tokens_user_purpose (user_id, purpose),
+    CONSTRAINT fk_user_tokens_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET @add_email_verified = IF(
+    (SELECT COUNT(*) FROM information_schema.COLUMNS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'users' AND COLUMN_NAME = 'email_verified') = 0,
+    'ALTER TABLE users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE',
+    'DO 0'
+);
+
+PREPARE add_email_verified FROM @add_email_verified;
+
+EXECUTE add_email_verified;
+
+DEALLOCATE PREPARE add_email_verified;
+
+-- Los usuarios que ya existían se consideran verificados para no
+-- bloquearlos al activar la verificación. Queda en la transacción de la
+-- migración: se confirma junto con su versión.
+UPDATE users SET email_verified = TRUE;
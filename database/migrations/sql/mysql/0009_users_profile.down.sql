@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN avatar_url, DROP COLUMN bio;
@@ -0,0 +1,4 @@
+-- Una sola sentencia: MySQL confirma cada DDL por separado
+ALTER TABLE users
+    ADD COLUMN bio VARCHAR(500) NOT NULL DEFAULT '',
+    ADD COLUMN avatar_url VARCHAR(255) NOT NULL DEFAULT '';
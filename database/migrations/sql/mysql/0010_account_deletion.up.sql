@@ -0,0 +1,12 @@
+-- Cuenta a la que se reasignan los posts de los usuarios eliminados cuando
+-- ACCOUNT_DELETION_MODE=anonymize. Su contraseña no es un hash bcrypt, así
+-- que nadie puede iniciar sesión con ella. Va antes del ALTER, que la
+-- confirma de forma implícita, y no se duplica si la migración se repite.
+INSERT INTO users (name, email, email_verified, password, role)
+SELECT 'Usuario eliminado', 'deleted-user@gopost.invalid', TRUE, '!', 'user' FROM DUAL
+WHERE NOT EXISTS (SELECT 1 FROM users WHERE email = 'deleted-user@gopost.invalid');
+
+-- Una sola sentencia DDL y al final: MySQL confirma cada DDL por separado
+ALTER TABLE users
+    ADD COLUMN deletion_requested_at TIMESTAMP NULL DEFAULT NULL,
+    ADD INDEX idx_users_deletion_requested_at (deletion_requested_at);
@@ -0,0 +1,7 @@
+-- Irreversible: no se conserva cómo estaban escritos los emails originales.
+-- Los emails se guardan en minúsculas y sin espacios alrededor. La
+-- comparación es binaria porque la collation _ci de la columna da por
+-- iguales 'Ana@Example.COM' y 'ana@example.com'. El índice único también
+-- ignora las mayúsculas, así que solo dos cuentas que difieran en espacios
+-- al inicio del email pueden chocar y detener la migración.
+UPDATE users SET email = LOWER(TRIM(email)) WHERE CAST(email AS BINARY) <> LOWER(TRIM(email));
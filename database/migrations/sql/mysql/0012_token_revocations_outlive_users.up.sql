@@ -0,0 +1,3 @@
+-- El corte de revocación debe sobrevivir al usuario: si se borrara con la
+-- cuenta, sus access tokens volverían a ser válidos hasta expirar
+ALTER TABLE user_token_revocations DROP FOREIGN KEY fk_user_token_revocations_user;
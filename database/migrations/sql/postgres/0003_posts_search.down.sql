@@ -0,0 +1 @@
+DROP INDEX IF EXISTS ft_posts_title_content;
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS ft_posts_title_content ON posts
+    USING GIN (to_tsvector('simple', title || ' ' || content));
@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN bio VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN avatar_url VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+DELETE FROM users
+WHERE email = 'deleted-user@gopost.invalid' AND password = '!'
+    AND NOT EXISTS (SELECT 1 FROM posts WHERE posts.user_id = users.id);
+
+DROP INDEX IF EXISTS idx_users_deletion_requested_at;
+
+ALTER TABLE users DROP COLUMN deletion_requested_at;
@@ -0,0 +1,9 @@
+ALTER TABLE users ADD COLUMN deletion_requested_at TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_requested_at ON users (deletion_requested_at);
+
+-- Cuenta a la que se reasignan los posts de los usuarios eliminados cuando
+-- ACCOUNT_DELETION_MODE=anonymize. Su contraseña no es un hash bcrypt, así
+-- que nadie puede iniciar sesión con ella.
+INSERT INTO users (name, email, email_verified, password, role)
+VALUES ('Usuario eliminado', 'deleted-user@gopost.invalid', TRUE, '!', 'user');
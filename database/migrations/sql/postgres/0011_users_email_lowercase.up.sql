@@ -0,0 +1,4 @@
+-- Los emails se guardan en minúsculas. Si dos cuentas solo difieren en
+-- mayúsculas, la restricción única detiene la migración para resolverlo
+-- a mano.
+UPDATE users SET email = LOWER(TRIM(email)) WHERE email <> LOWER(TRIM(email));
@@ -0,0 +1,5 @@
+DELETE FROM user_token_revocations
+WHERE NOT EXISTS (SELECT 1 FROM users WHERE users.id = user_token_revocations.user_id);
+
+ALTER TABLE user_token_revocations
+    ADD CONSTRAINT user_token_revocations_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
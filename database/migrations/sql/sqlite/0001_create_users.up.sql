@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL COLLATE NOCASE,
+    password TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_users_email ON users (email);
@@ -0,0 +1,3 @@
+-- La búsqueda en SQLite usa LIKE sobre title y content, que no aprovecha
+-- índices; esta versión existe para mantener la numeración de los dialectos.
+SELECT 1;
@@ -0,0 +1,5 @@
+ALTER TABLE users DROP COLUMN last_login_at;
+
+ALTER TABLE users DROP COLUMN updated_at;
+
+ALTER TABLE users DROP COLUMN created_at;
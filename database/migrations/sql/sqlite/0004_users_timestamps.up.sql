@@ -0,0 +1,9 @@
+-- SQLite no admite ADD COLUMN con DEFAULT CURRENT_TIMESTAMP: se usa un valor
+-- constante y se completa con la fecha actual para los usuarios existentes
+ALTER TABLE users ADD COLUMN created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+
+ALTER TABLE users ADD COLUMN updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+
+ALTER TABLE users ADD COLUMN last_login_at DATETIME NULL;
+
+UPDATE users SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP;
@@ -0,0 +1 @@
+-- Irreversible: no se conserva cómo estaban escritos los emails originales.
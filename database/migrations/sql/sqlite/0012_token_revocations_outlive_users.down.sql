@@ -0,0 +1,12 @@
+CREATE TABLE user_token_revocations_old (
+    user_id INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    revoked_before DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO user_token_revocations_old (user_id, revoked_before)
+SELECT user_id, revoked_before FROM user_token_revocations
+WHERE user_id IN (SELECT id FROM users);
+
+DROP TABLE user_token_revocations;
+
+ALTER TABLE user_token_revocations_old RENAME TO user_token_revocations;
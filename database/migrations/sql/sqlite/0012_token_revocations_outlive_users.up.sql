@@ -0,0 +1,14 @@
+-- El corte de revocación debe sobrevivir al usuario: si se borrara con la
+-- cuenta, sus access tokens volverían a ser válidos hasta expirar. SQLite
+-- no permite quitar una clave foránea, así que se reconstruye la tabla.
+CREATE TABLE user_token_revocations_new (
+    user_id INTEGER PRIMARY KEY,
+    revoked_before DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO user_token_revocations_new (user_id, revoked_before)
+SELECT user_id, revoked_before FROM user_token_revocations;
+
+DROP TABLE user_token_revocations;
+
+ALTER TABLE user_token_revocations_new RENAME TO user_token_revocations;
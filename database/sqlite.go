@@ -0,0 +1,26 @@
+//go:build cgo
+
+package database
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteSupported indica si el binario incluye el driver de SQLite. El
+// driver requiere cgo: los binarios estáticos compilados con CGO_ENABLED=0
+// solo incluyen MySQL y PostgreSQL.
+const sqliteSupported = true
+
+// El LOWER de SQLite solo pasa a minúsculas las letras ASCII. Cada conexión
+// registra unicode_lower, que usa strings.ToLower como el resto de la API,
+// para que las búsquedas traten igual "Álvaro" y "álvaro".
+func init() {
+	sql.Register(DriverSQLite, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("unicode_lower", strings.ToLower, true)
+		},
+	})
+}
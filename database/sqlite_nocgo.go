@@ -0,0 +1,6 @@
+//go:build !cgo
+
+package database
+
+// sqliteSupported indica si el binario incluye el driver de SQLite
+const sqliteSupported = false
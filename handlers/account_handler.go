@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gopost-api/server"
+	"github.com/gopost-api/services"
+)
+
+// UpdateMeHandler modifica el nombre y el perfil del usuario autenticado.
+// Solo se cambian los campos presentes en el cuerpo.
+func (h *UserHandler) UpdateMeHandler(c *server.Context) error {
+	userID := c.GetUserID()
+	if userID == 0 {
+		return NewAppError("Usuario no autenticado", http.StatusUnauthorized)
+	}
+
+	var req struct {
+		Name      *string `json:"name"`
+		Bio       *string `json:"bio"`
+		AvatarURL *string `json:"avatar_url"`
+	}
+
+	if err := c.BindJSON(&req); err != nil {
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
+	}
+
+	if req.Name == nil && req.Bio == nil && req.AvatarURL == nil {
+		return NewAppError("Debe enviar al menos un campo: name, bio o avatar_url", http.StatusBadRequest)
+	}
+
+	user, err := h.userService.UpdateProfile(c.Context(), userID, services.ProfileUpdate{
+		Name:      req.Name,
+		Bio:       req.Bio,
+		AvatarURL: req.AvatarURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
+		"message": "Perfil actualizado exitosamente",
+		"user":    user,
+	})
+	return nil
+}
+
+// ChangePasswordHandler cambia la contraseña del usuario autenticado. Cierra
+// sus demás sesiones y responde con tokens nuevos para la actual.
+func (h *UserHandler) ChangePasswordHandler(c *server.Context) error {
+	userID := c.GetUserID()
+	if userID == 0 {
+		return NewAppError("Usuario no autenticado", http.StatusUnauthorized)
+	}
+
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+
+	if err := c.BindJSON(&req); err != nil {
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
+	}
+
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		return NewAppError("La contraseña actual y la nueva son requeridas", http.StatusBadRequest)
+	}
+
+	tokens, err := h.userService.ChangePassword(c.Context(), userID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	respondTokens(c, "Contraseña actualizada exitosamente", tokens)
+	return nil
+}
+
+// RequestEmailChangeHandler envía un token de confirmación a la nueva
+// dirección; el email de la cuenta cambia al confirmarlo
+func (h *UserHandler) RequestEmailChangeHandler(c *server.Context) error {
+	userID := c.GetUserID()
+	if userID == 0 {
+		return NewAppError("Usuario no autenticado", http.StatusUnauthorized)
+	}
+
+	var req struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+
+	if err := c.BindJSON(&req); err != nil {
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
+	}
+
+	if req.Email == "" || req.Password == "" {
+		return NewAppError("El email y la contraseña son requeridos", http.StatusBadRequest)
+	}
+
+	if err := h.userService.RequestEmailChange(c.Context(), userID, req.Email, req.Password); err != nil {
+		return err
+	}
+
+	RespondJSON(c.RWriter, http.StatusAccepted, map[string]interface{}{
+		"message": "Enviamos un correo a la nueva dirección para confirmar el cambio",
+	})
+	return nil
+}
+
+// ConfirmEmailChangeHandler aplica el cambio de email con el token enviado
+// a la nueva dirección
+func (h *UserHandler) ConfirmEmailChangeHandler(c *server.Context) error {
+	var req struct {
+		Token string `json:"token"`
+	}
+
+	if err := c.BindJSON(&req); err != nil {
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
+	}
+
+	if req.Token == "" {
+		return NewAppError("El token es requerido", http.StatusBadRequest)
+	}
+
+	user, err := h.userService.ConfirmEmailChange(c.Context(), req.Token)
+	if err != nil {
+		return err
+	}
+
+	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
+		"message": "Email actualizado exitosamente",
+		"user":    user,
+	})
+	return nil
+}
+
+// DeleteMeHandler pide borrar la cuenta del usuario autenticado. Cierra sus
+// sesiones y, si hay período de gracia, iniciar sesión antes de que venza
+// cancela el borrado.
+func (h *UserHandler) DeleteMeHandler(c *server.Context) error {
+	userID := c.GetUserID()
+	if userID == 0 {
+		return NewAppError("Usuario no autenticado", http.StatusUnauthorized)
+	}
+
+	var req struct {
+		Password string `json:"password"`
+	}
+
+	if err := c.BindJSON(&req); err != nil {
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
+	}
+
+	if req.Password == "" {
+		return NewAppError("La contraseña es requerida", http.StatusBadRequest)
+	}
+
+	purgeAt, err := h.userService.DeleteAccount(c.Context(), userID, req.Password)
+	if err != nil {
+		return err
+	}
+
+	if purgeAt == nil {
+		RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
+			"message": "Cuenta eliminada exitosamente",
+		})
+		return nil
+	}
+
+	RespondJSON(c.RWriter, http.StatusAccepted, map[string]interface{}{
+		"message":  "La cuenta se eliminará al vencer el período de gracia. Inicia sesión antes para cancelarlo",
+		"purge_at": purgeAt,
+	})
+	return nil
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/server"
+	"github.com/gopost-api/services"
+)
+
+type AdminHandler struct {
+	userService *services.UserService
+}
+
+func NewAdminHandler(userService *services.UserService) *AdminHandler {
+	return &AdminHandler{userService: userService}
+}
+
+// UpdateUserRoleHandler asigna el rol de un usuario
+func (h *AdminHandler) UpdateUserRoleHandler(c *server.Context) error {
+	actor, err := currentActor(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := c.ParamUint("id")
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		Role models.Role `json:"role"`
+	}
+
+	if err := c.BindJSON(&req); err != nil {
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
+	}
+
+	if req.Role == "" {
+		return NewAppError("El rol es requerido", http.StatusBadRequest)
+	}
+
+	user, err := h.userService.ChangeRole(c.Context(), actor, id, req.Role)
+	if err != nil {
+		return err
+	}
+
+	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
+		"message": "Rol actualizado exitosamente",
+		"user":    user,
+	})
+	return nil
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gopost-api/server"
+	"github.com/gopost-api/services"
+)
+
+// tokenClaims obtiene los claims que AuthMiddleware dejó en el contexto
+func tokenClaims(c *server.Context) (*services.AccessClaims, bool) {
+	value, ok := c.Get(services.ClaimsKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*services.AccessClaims)
+	return claims, ok
+}
+
+// currentActor arma el Actor de la petición autenticada para las
+// políticas de los servicios
+func currentActor(c *server.Context) (services.Actor, error) {
+	claims, ok := tokenClaims(c)
+	if !ok || claims.UserID == 0 {
+		return services.Actor{}, NewAppError("Usuario no autenticado", http.StatusUnauthorized)
+	}
+	return services.Actor{UserID: claims.UserID, Role: claims.Role}, nil
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gopost-api/server"
+)
+
+// VerifyEmailHandler canjea el token enviado por correo al registrarse
+func (h *UserHandler) VerifyEmailHandler(c *server.Context) error {
+	var req struct {
+		Token string `json:"token"`
+	}
+
+	if err := c.BindJSON(&req); err != nil {
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
+	}
+
+	if req.Token == "" {
+		return NewAppError("El token es requerido", http.StatusBadRequest)
+	}
+
+	user, err := h.userService.VerifyEmail(c.Context(), req.Token)
+	if err != nil {
+		return err
+	}
+
+	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
+		"message": "Email verificado exitosamente",
+		"user":    user,
+	})
+	return nil
+}
+
+// ResendVerificationHandler envía otro correo de verificación. Responde
+// igual exista o no la cuenta.
+func (h *UserHandler) ResendVerificationHandler(c *server.Context) error {
+	var req struct {
+		Email string `json:"email"`
+	}
+
+	if err := c.BindJSON(&req); err != nil {
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
+	}
+
+	if req.Email == "" {
+		return NewAppError("El email es requerido", http.StatusBadRequest)
+	}
+
+	if err := h.userService.ResendVerification(c.Context(), req.Email); err != nil {
+		return err
+	}
+
+	RespondJSON(c.RWriter, http.StatusAccepted, map[string]interface{}{
+		"message": "Si la cuenta existe y no está verificada, enviamos un nuevo correo de verificación",
+	})
+	return nil
+}
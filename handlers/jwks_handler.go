@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gopost-api/server"
+	"github.com/gopost-api/services"
+)
+
+type JWKSHandler struct {
+	tokenService *services.TokenService
+}
+
+func NewJWKSHandler(tokenService *services.TokenService) *JWKSHandler {
+	return &JWKSHandler{tokenService: tokenService}
+}
+
+// JWKSHandler publica las claves públicas para que otros servicios
+// verifiquen los access tokens sin compartir ningún secreto. Con HS256 la
+// lista está vacía.
+func (h *JWKSHandler) JWKSHandler(c *server.Context) error {
+	// Las claves cambian solo al rotarlas; los clientes pueden guardarlas
+	// un rato y volver a pedirlas cuando encuentren un kid desconocido
+	c.RWriter.Header().Set("Cache-Control", "public, max-age=300")
+	RespondJSON(c.RWriter, http.StatusOK, h.tokenService.JWKS())
+	return nil
+}
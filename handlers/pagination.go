@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gopost-api/server"
+	"github.com/gopost-api/services"
+)
+
+// PageMeta son los metadatos de paginación que acompañan a un listado
+type PageMeta struct {
+	Page       int    `json:"page"`
+	PerPage    int    `json:"per_page"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	Sort       string `json:"sort,omitempty"`
+}
+
+func newPageMeta(page, perPage, total int, sort string) PageMeta {
+	return PageMeta{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: (total + perPage - 1) / perPage,
+		Sort:       sort,
+	}
+}
+
+// bindPostListParams lee page, per_page, sort, user_id, created_after y
+// created_before de la query string
+func bindPostListParams(c *server.Context) (services.PostListParams, error) {
+	var params services.PostListParams
+	var err error
+
+	if params.Page, err = c.QueryInt("page", 1); err != nil {
+		return params, err
+	}
+	if params.PerPage, err = c.QueryInt("per_page", 0); err != nil {
+		return params, err
+	}
+	if params.UserID, err = c.QueryUint("user_id", 0); err != nil {
+		return params, err
+	}
+	if params.CreatedAfter, err = c.QueryTime("created_after"); err != nil {
+		return params, err
+	}
+	if params.CreatedBefore, err = c.QueryTime("created_before"); err != nil {
+		return params, err
+	}
+	params.Sort = c.Query("sort", "")
+
+	return params, nil
+}
+
+// FeedMeta son los metadatos de una página del feed por cursor.
+// NextCursor es null cuando no hay más posts.
+type FeedMeta struct {
+	Limit      int     `json:"limit"`
+	NextCursor *string `json:"next_cursor"`
+	HasMore    bool    `json:"has_more"`
+}
+
+func newFeedMeta(feed *services.PostFeed) FeedMeta {
+	meta := FeedMeta{Limit: feed.Limit, HasMore: feed.HasMore}
+	if feed.HasMore {
+		meta.NextCursor = &feed.NextCursor
+	}
+	return meta
+}
+
+// bindPostFeedParams lee cursor, limit y user_id de la query string. El feed
+// siempre se ordena por -created_at, así que otro sort es un error.
+func bindPostFeedParams(c *server.Context) (services.PostFeedParams, error) {
+	var params services.PostFeedParams
+	var err error
+
+	if sort := c.Query("sort", "-created_at"); sort != "-created_at" {
+		return params, NewAppError("La paginación por cursor solo admite sort=-created_at", http.StatusBadRequest)
+	}
+	if params.Limit, err = c.QueryInt("limit", 0); err != nil {
+		return params, err
+	}
+	if params.UserID, err = c.QueryUint("user_id", 0); err != nil {
+		return params, err
+	}
+	params.Cursor = c.Query("cursor", "")
+
+	return params, nil
+}
+
+// setNextLinkHeader agrega la cabecera Link con la siguiente página del feed
+func setNextLinkHeader(c *server.Context, meta FeedMeta) {
+	if meta.NextCursor == nil {
+		return
+	}
+	query := c.Request.URL.Query()
+	query.Set("cursor", *meta.NextCursor)
+	query.Set("limit", strconv.Itoa(meta.Limit))
+	u := url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}
+	c.RWriter.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", u.String()))
+}
+
+// setLinkHeader agrega la cabecera Link (RFC 8288) con las páginas first,
+// prev, next y last, conservando el resto de la query string
+func setLinkHeader(c *server.Context, meta PageMeta) {
+	link := func(page int, rel string) string {
+		query := c.Request.URL.Query()
+		query.Set("page", strconv.Itoa(page))
+		query.Set("per_page", strconv.Itoa(meta.PerPage))
+		u := url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}
+		return fmt.Sprintf("<%s>; rel=\"%s\"", u.String(), rel)
+	}
+
+	lastPage := meta.TotalPages
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	links := []string{link(1, "first")}
+	if meta.Page > 1 {
+		prev := meta.Page - 1
+		if prev > lastPage {
+			prev = lastPage
+		}
+		links = append(links, link(prev, "prev"))
+	}
+	if meta.Page < lastPage {
+		links = append(links, link(meta.Page+1, "next"))
+	}
+	links = append(links, link(lastPage, "last"))
+
+	c.RWriter.Header().Set("Link", strings.Join(links, ", "))
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gopost-api/server"
+)
+
+// ForgotPasswordHandler envía el enlace para restablecer la contraseña.
+// Siempre responde 202 para no revelar qué emails están registrados.
+func (h *UserHandler) ForgotPasswordHandler(c *server.Context) error {
+	var req struct {
+		Email string `json:"email"`
+	}
+
+	if err := c.BindJSON(&req); err != nil {
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
+	}
+
+	if req.Email == "" {
+		return NewAppError("El email es requerido", http.StatusBadRequest)
+	}
+
+	if err := h.userService.ForgotPassword(c.Context(), req.Email); err != nil {
+		return err
+	}
+
+	RespondJSON(c.RWriter, http.StatusAccepted, map[string]interface{}{
+		"message": "Si el email está registrado, enviamos un enlace para restablecer la contraseña",
+	})
+	return nil
+}
+
+// ResetPasswordHandler fija una nueva contraseña con el token recibido por
+// correo. Todas las sesiones del usuario quedan cerradas.
+func (h *UserHandler) ResetPasswordHandler(c *server.Context) error {
+	var req struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	if err := c.BindJSON(&req); err != nil {
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
+	}
+
+	if req.Token == "" || req.Password == "" {
+		return NewAppError("El token y la contraseña son requeridos", http.StatusBadRequest)
+	}
+
+	if err := h.userService.ResetPassword(c.Context(), req.Token, req.Password); err != nil {
+		return err
+	}
+
+	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
+		"message": "Contraseña restablecida exitosamente. Inicia sesión con la nueva contraseña",
+	})
+	return nil
+}
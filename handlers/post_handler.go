@@ -2,8 +2,6 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
-	"strings"
 
 	"github.com/gopost-api/server"
 	"github.com/gopost-api/services"
@@ -17,11 +15,10 @@ func NewPostHandler(postService *services.PostService) *PostHandler {
 	return &PostHandler{postService: postService}
 }
 
-func (h *PostHandler) CreatePostHandler(c *server.Context) {
+func (h *PostHandler) CreatePostHandler(c *server.Context) error {
 	userID := c.GetUserID()
 	if userID == 0 {
-		RespondError(c.RWriter, NewAppError("Usuario no autenticado", http.StatusUnauthorized))
-		return
+		return NewAppError("Usuario no autenticado", http.StatusUnauthorized)
 	}
 
 	var req struct {
@@ -30,77 +27,100 @@ func (h *PostHandler) CreatePostHandler(c *server.Context) {
 	}
 
 	if err := c.BindJSON(&req); err != nil {
-		RespondError(c.RWriter, NewAppError("Datos inválidos", http.StatusBadRequest))
-		return
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
 	}
 
 	post, err := h.postService.CreatePost(c.Context(), userID, req.Title, req.Content)
 	if err != nil {
-		RespondError(c.RWriter, NewAppError(err.Error(), http.StatusBadRequest))
-		return
+		return err
 	}
 
 	RespondJSON(c.RWriter, http.StatusCreated, map[string]interface{}{
 		"message": "Post creado exitosamente",
 		"post":    post,
 	})
+	return nil
 }
 
-func (h *PostHandler) GetPostsHandler(c *server.Context) {
-	posts, err := h.postService.GetAllPosts(c.Context())
+// GetPostsHandler lista los posts paginados. Admite ?page=&per_page=,
+// ?sort=created_at|-updated_at|title y los filtros ?user_id=,
+// ?created_after= y ?created_before=. Con ?cursor= (vacío en la primera
+// página) y ?limit= usa paginación por cursor para feeds infinitos.
+func (h *PostHandler) GetPostsHandler(c *server.Context) error {
+	if c.HasQuery("cursor") {
+		params, err := bindPostFeedParams(c)
+		if err != nil {
+			return err
+		}
+		return h.respondPostFeed(c, params)
+	}
+
+	params, err := bindPostListParams(c)
 	if err != nil {
-		RespondError(c.RWriter, NewAppError(err.Error(), http.StatusInternalServerError))
-		return
+		return err
 	}
 
-	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
-		"posts": posts,
-	})
+	return h.respondPostPage(c, params)
 }
 
-func (h *PostHandler) GetPostHandler(c *server.Context) {
-	pathParts := strings.Split(c.Request.URL.Path, "/")
-	if len(pathParts) < 3 {
-		RespondError(c.RWriter, NewAppError("ID de post inválido", http.StatusBadRequest))
-		return
+// SearchPostsHandler busca posts por título y contenido con ?q=, ordenados
+// por relevancia y paginados con ?page=&per_page=
+func (h *PostHandler) SearchPostsHandler(c *server.Context) error {
+	page, err := c.QueryInt("page", 1)
+	if err != nil {
+		return err
 	}
-
-	idStr := pathParts[len(pathParts)-1]
-	id, err := strconv.ParseUint(idStr, 10, 32)
+	perPage, err := c.QueryInt("per_page", 0)
 	if err != nil {
-		RespondError(c.RWriter, NewAppError("ID de post inválido", http.StatusBadRequest))
-		return
+		return err
 	}
 
-	post, err := h.postService.GetPostByID(c.Context(), uint(id))
+	results, err := h.postService.Search(c.Context(), services.PostSearchParams{
+		Query:   c.Query("q", ""),
+		Page:    page,
+		PerPage: perPage,
+	})
 	if err != nil {
-		RespondError(c.RWriter, NewAppError("Post no encontrado", http.StatusNotFound))
-		return
+		return err
 	}
 
+	meta := newPageMeta(results.Page, results.PerPage, results.Total, "")
+	setLinkHeader(c, meta)
+
 	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
-		"post": post,
+		"query":   results.Query,
+		"results": results.Results,
+		"meta":    meta,
 	})
+	return nil
 }
 
-func (h *PostHandler) UpdatePostHandler(c *server.Context) {
-	userID := c.GetUserID()
-	if userID == 0 {
-		RespondError(c.RWriter, NewAppError("Usuario no autenticado", http.StatusUnauthorized))
-		return
+func (h *PostHandler) GetPostHandler(c *server.Context) error {
+	id, err := c.ParamUint("id")
+	if err != nil {
+		return err
 	}
 
-	pathParts := strings.Split(c.Request.URL.Path, "/")
-	if len(pathParts) < 3 {
-		RespondError(c.RWriter, NewAppError("ID de post inválido", http.StatusBadRequest))
-		return
+	post, err := h.postService.GetPostByID(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
+		"post": post,
+	})
+	return nil
+}
+
+func (h *PostHandler) UpdatePostHandler(c *server.Context) error {
+	actor, err := currentActor(c)
+	if err != nil {
+		return err
 	}
 
-	idStr := pathParts[len(pathParts)-1]
-	id, err := strconv.ParseUint(idStr, 10, 32)
+	id, err := c.ParamUint("id")
 	if err != nil {
-		RespondError(c.RWriter, NewAppError("ID de post inválido", http.StatusBadRequest))
-		return
+		return err
 	}
 
 	var req struct {
@@ -109,66 +129,98 @@ func (h *PostHandler) UpdatePostHandler(c *server.Context) {
 	}
 
 	if err := c.BindJSON(&req); err != nil {
-		RespondError(c.RWriter, NewAppError("Datos inválidos", http.StatusBadRequest))
-		return
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
 	}
 
-	post, err := h.postService.UpdatePost(c.Context(), uint(id), userID, req.Title, req.Content)
+	post, err := h.postService.UpdatePost(c.Context(), actor, id, req.Title, req.Content)
 	if err != nil {
-		RespondError(c.RWriter, NewAppError(err.Error(), http.StatusBadRequest))
-		return
+		return err
 	}
 
 	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
 		"message": "Post actualizado exitosamente",
 		"post":    post,
 	})
+	return nil
 }
 
-func (h *PostHandler) DeletePostHandler(c *server.Context) {
-	userID := c.GetUserID()
-	if userID == 0 {
-		RespondError(c.RWriter, NewAppError("Usuario no autenticado", http.StatusUnauthorized))
-		return
-	}
-
-	pathParts := strings.Split(c.Request.URL.Path, "/")
-	if len(pathParts) < 3 {
-		RespondError(c.RWriter, NewAppError("ID de post inválido", http.StatusBadRequest))
-		return
+func (h *PostHandler) DeletePostHandler(c *server.Context) error {
+	actor, err := currentActor(c)
+	if err != nil {
+		return err
 	}
 
-	idStr := pathParts[len(pathParts)-1]
-	id, err := strconv.ParseUint(idStr, 10, 32)
+	id, err := c.ParamUint("id")
 	if err != nil {
-		RespondError(c.RWriter, NewAppError("ID de post inválido", http.StatusBadRequest))
-		return
+		return err
 	}
 
-	if err := h.postService.DeletePost(c.Context(), uint(id), userID); err != nil {
-		RespondError(c.RWriter, NewAppError(err.Error(), http.StatusBadRequest))
-		return
+	if err := h.postService.DeletePost(c.Context(), actor, id); err != nil {
+		return err
 	}
 
 	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
 		"message": "Post eliminado exitosamente",
 	})
+	return nil
 }
 
-func (h *PostHandler) GetPostMeHandler(c *server.Context) {
+func (h *PostHandler) GetPostMeHandler(c *server.Context) error {
 	userID := c.GetUserID()
 	if userID == 0 {
-		RespondError(c.RWriter, NewAppError("Usuario no autenticado", http.StatusUnauthorized))
-		return
+		return NewAppError("Usuario no autenticado", http.StatusUnauthorized)
 	}
 
-	posts, err := h.postService.GetPostsByUserID(c.Context(), userID)
+	if c.HasQuery("cursor") {
+		params, err := bindPostFeedParams(c)
+		if err != nil {
+			return err
+		}
+		params.UserID = userID
+		return h.respondPostFeed(c, params)
+	}
+
+	params, err := bindPostListParams(c)
 	if err != nil {
-		RespondError(c.RWriter, NewAppError(err.Error(), http.StatusInternalServerError))
-		return
+		return err
 	}
+	params.UserID = userID
+
+	return h.respondPostPage(c, params)
+}
+
+// respondPostPage obtiene la página y responde con los posts, los metadatos
+// de paginación y la cabecera Link
+func (h *PostHandler) respondPostPage(c *server.Context, params services.PostListParams) error {
+	page, err := h.postService.ListPosts(c.Context(), params)
+	if err != nil {
+		return err
+	}
+
+	meta := newPageMeta(page.Page, page.PerPage, page.Total, page.Sort)
+	setLinkHeader(c, meta)
+
+	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
+		"posts": page.Posts,
+		"meta":  meta,
+	})
+	return nil
+}
+
+// respondPostFeed responde una página del feed por cursor con next_cursor
+// y la cabecera Link rel="next" cuando hay más posts
+func (h *PostHandler) respondPostFeed(c *server.Context, params services.PostFeedParams) error {
+	feed, err := h.postService.FeedPosts(c.Context(), params)
+	if err != nil {
+		return err
+	}
+
+	meta := newFeedMeta(feed)
+	setNextLinkHeader(c, meta)
 
 	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
-		"posts": posts,
+		"posts": feed.Posts,
+		"meta":  meta,
 	})
+	return nil
 }
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/repositories/memory"
+	"github.com/gopost-api/server"
+	"github.com/gopost-api/services"
+)
+
+// newTestPostsApp registra GET /posts sobre los stores en memoria con
+// count posts publicados
+func newTestPostsApp(t *testing.T, count int) *server.App {
+	t.Helper()
+	ctx := context.Background()
+	users, posts := memory.NewUserStore(), memory.NewPostStore()
+	cursors, err := services.NewSigner("CURSOR_SECRET", strings.Repeat("c", 32))
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+	postService := services.NewPostService(posts, users, cursors, false)
+
+	user := &models.User{Name: "Ana", Email: "ana@example.com", Password: "x"}
+	if err := users.Create(ctx, user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	for i := 1; i <= count; i++ {
+		if _, err := postService.CreatePost(ctx, user.ID, fmt.Sprintf("Post %d", i), "Contenido"); err != nil {
+			t.Fatalf("CreatePost: %v", err)
+		}
+	}
+
+	app := server.New()
+	app.Get("/posts", server.Handler(NewPostHandler(postService).GetPostsHandler))
+	return app
+}
+
+var linkPattern = regexp.MustCompile(`<([^>]*)>; rel="([^"]*)"`)
+
+// getLinks hace la petición y devuelve las URLs de la cabecera Link por rel
+func getLinks(t *testing.T, app *server.App, target string) (map[string]*url.URL, *httptest.ResponseRecorder) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s = %d: %s", target, rec.Code, rec.Body.String())
+	}
+
+	links := map[string]*url.URL{}
+	for _, match := range linkPattern.FindAllStringSubmatch(rec.Header().Get("Link"), -1) {
+		u, err := url.Parse(match[1])
+		if err != nil {
+			t.Fatalf("URL inválida en Link: %q", match[1])
+		}
+		links[match[2]] = u
+	}
+	return links, rec
+}
+
+func TestGetPostsLinkHeader(t *testing.T) {
+	app := newTestPostsApp(t, 5)
+
+	tests := []struct {
+		name      string
+		page      int
+		wantPages map[string]string
+	}{
+		{name: "primera página", page: 1, wantPages: map[string]string{"first": "1", "next": "2", "last": "3"}},
+		{name: "página del medio", page: 2, wantPages: map[string]string{"first": "1", "prev": "1", "next": "3", "last": "3"}},
+		{name: "última página", page: 3, wantPages: map[string]string{"first": "1", "prev": "2", "last": "3"}},
+		{name: "más allá de la última", page: 9, wantPages: map[string]string{"first": "1", "prev": "3", "last": "3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, _ := getLinks(t, app, fmt.Sprintf("/posts?page=%d&per_page=2&sort=title&user_id=1", tt.page))
+
+			if len(links) != len(tt.wantPages) {
+				t.Errorf("Link tiene %d relaciones, se esperaban %v", len(links), tt.wantPages)
+			}
+			for rel, wantPage := range tt.wantPages {
+				u, ok := links[rel]
+				if !ok {
+					t.Errorf("falta rel=%q", rel)
+					continue
+				}
+				query := u.Query()
+				if u.Path != "/posts" || query.Get("page") != wantPage || query.Get("per_page") != "2" {
+					t.Errorf("rel=%q = %s, se esperaba page=%s y per_page=2", rel, u, wantPage)
+				}
+				// El resto de la query string se conserva
+				if query.Get("sort") != "title" || query.Get("user_id") != "1" {
+					t.Errorf("rel=%q = %s, perdió sort o user_id", rel, u)
+				}
+			}
+		})
+	}
+}
+
+func TestGetPostsFeedLinkHeader(t *testing.T) {
+	app := newTestPostsApp(t, 5)
+
+	target := "/posts?cursor=&limit=2&user_id=1"
+	for page := 1; ; page++ {
+		links, rec := getLinks(t, app, target)
+		var body struct {
+			Meta FeedMeta `json:"meta"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("cuerpo no es JSON: %q", rec.Body.String())
+		}
+
+		// En la última página no hay siguiente
+		if page == 3 {
+			if rec.Header().Get("Link") != "" {
+				t.Errorf("la última página trae Link %q", rec.Header().Get("Link"))
+			}
+			if body.Meta.HasMore || body.Meta.NextCursor != nil {
+				t.Errorf("meta = %+v, no debía haber más posts", body.Meta)
+			}
+			return
+		}
+
+		next, ok := links["next"]
+		if len(links) != 1 || !ok {
+			t.Fatalf("página %d: Link = %q, se esperaba solo rel=\"next\"", page, rec.Header().Get("Link"))
+		}
+		query := next.Query()
+		if body.Meta.NextCursor == nil || query.Get("cursor") != *body.Meta.NextCursor {
+			t.Errorf("página %d: cursor = %q, se esperaba next_cursor %v", page, query.Get("cursor"), body.Meta.NextCursor)
+		}
+		if next.Path != "/posts" || query.Get("limit") != "2" || query.Get("user_id") != "1" {
+			t.Errorf("página %d: next = %s, perdió limit o user_id", page, next)
+		}
+		target = next.String()
+	}
+}
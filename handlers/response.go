@@ -3,13 +3,12 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/gopost-api/server"
 )
 
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-	Code    int    `json:"code"`
-}
+// ErrorResponse es el mismo cuerpo de error que usa el ErrorHandler del servidor
+type ErrorResponse = server.ErrorResponse
 
 type AppError struct {
 	Message string
@@ -20,6 +19,11 @@ func (e *AppError) Error() string {
 	return e.Message
 }
 
+// StatusCode permite que el ErrorHandler del servidor respete el código elegido
+func (e *AppError) StatusCode() int {
+	return e.Code
+}
+
 func NewAppError(message string, code int) *AppError {
 	return &AppError{
 		Message: message,
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gopost-api/server"
 	"github.com/gopost-api/services"
@@ -15,7 +16,7 @@ func NewUserHandler(userService *services.UserService) *UserHandler {
 	return &UserHandler{userService: userService}
 }
 
-func (h *UserHandler) SignUpHandler(c *server.Context) {
+func (h *UserHandler) SignUpHandler(c *server.Context) error {
 	var req struct {
 		Name     string `json:"name"`
 		Email    string `json:"email"`
@@ -23,77 +24,138 @@ func (h *UserHandler) SignUpHandler(c *server.Context) {
 	}
 
 	if err := c.BindJSON(&req); err != nil {
-		RespondError(c.RWriter, NewAppError("Datos inválidos", http.StatusBadRequest))
-		return
-	}
-
-	if req.Name == "" || req.Email == "" || req.Password == "" {
-		RespondError(c.RWriter, NewAppError("Todos los campos son requeridos", http.StatusBadRequest))
-		return
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
 	}
 
+	// SignUp valida cada campo y responde con los errores en "fields"
 	user, err := h.userService.SignUp(c.Context(), req.Name, req.Email, req.Password)
 	if err != nil {
-		RespondError(c.RWriter, NewAppError(err.Error(), http.StatusBadRequest))
-		return
+		return err
 	}
 
 	RespondJSON(c.RWriter, http.StatusCreated, map[string]interface{}{
 		"message": "Usuario registrado exitosamente",
 		"user": map[string]interface{}{
-			"id":    user.ID,
-			"name":  user.Name,
-			"email": user.Email,
+			"id":             user.ID,
+			"name":           user.Name,
+			"email":          user.Email,
+			"email_verified": user.EmailVerified,
 		},
 	})
+	return nil
 }
 
-func (h *UserHandler) LoginHandler(c *server.Context) {
+func (h *UserHandler) LoginHandler(c *server.Context) error {
 	var req struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
 	}
 
 	if err := c.BindJSON(&req); err != nil {
-		RespondError(c.RWriter, NewAppError("Datos inválidos", http.StatusBadRequest))
-		return
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
 	}
 
 	if req.Email == "" || req.Password == "" {
-		RespondError(c.RWriter, NewAppError("Email y contraseña son requeridos", http.StatusBadRequest))
-		return
+		return NewAppError("Email y contraseña son requeridos", http.StatusBadRequest)
 	}
 
-	token, err := h.userService.Login(c.Context(), req.Email, req.Password)
+	tokens, err := h.userService.Login(c.Context(), req.Email, req.Password)
 	if err != nil {
-		RespondError(c.RWriter, NewAppError(err.Error(), http.StatusUnauthorized))
-		return
+		return err
+	}
+
+	respondTokens(c, "Inicio de sesión exitoso", tokens)
+	return nil
+}
+
+// RefreshHandler canjea un refresh token por un par nuevo. El token usado
+// queda invalidado; volver a enviarlo revoca la sesión completa.
+func (h *UserHandler) RefreshHandler(c *server.Context) error {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := c.BindJSON(&req); err != nil {
+		return NewAppError("Datos inválidos", http.StatusBadRequest)
+	}
+
+	if req.RefreshToken == "" {
+		return NewAppError("El refresh token es requerido", http.StatusBadRequest)
+	}
+
+	tokens, err := h.userService.Refresh(c.Context(), req.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	respondTokens(c, "Token renovado exitosamente", tokens)
+	return nil
+}
+
+// LogoutHandler revoca el access token de la petición y los refresh tokens
+// de su sesión
+func (h *UserHandler) LogoutHandler(c *server.Context) error {
+	claims, ok := tokenClaims(c)
+	if !ok {
+		return NewAppError("Usuario no autenticado", http.StatusUnauthorized)
+	}
+
+	if err := h.userService.Logout(c.Context(), claims); err != nil {
+		return err
+	}
+
+	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
+		"message": "Sesión cerrada exitosamente",
+	})
+	return nil
+}
+
+// LogoutAllHandler revoca todos los tokens emitidos para el usuario, en
+// todos sus dispositivos
+func (h *UserHandler) LogoutAllHandler(c *server.Context) error {
+	userID := c.GetUserID()
+	if userID == 0 {
+		return NewAppError("Usuario no autenticado", http.StatusUnauthorized)
+	}
+
+	if err := h.userService.LogoutAll(c.Context(), userID); err != nil {
+		return err
 	}
 
 	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
-		"message": "Inicio de sesión exitoso",
-		"token":   token,
+		"message": "Se cerraron todas las sesiones",
 	})
+	return nil
 }
 
-func (h *UserHandler) MeHandler(c *server.Context) {
+// respondTokens responde con el par de tokens. "token" es el access token,
+// con el mismo nombre que antes para no romper a los clientes existentes.
+func respondTokens(c *server.Context, message string, tokens *services.TokenPair) {
+	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
+		"message":            message,
+		"token":              tokens.AccessToken,
+		"token_type":         "Bearer",
+		"expires_in":         int(time.Until(tokens.AccessExpiresAt).Round(time.Second).Seconds()),
+		"refresh_token":      tokens.RefreshToken,
+		"refresh_expires_at": tokens.RefreshExpiresAt,
+	})
+}
+
+func (h *UserHandler) MeHandler(c *server.Context) error {
 	userID := c.GetUserID()
 	if userID == 0 {
-		RespondError(c.RWriter, NewAppError("Usuario no autenticado", http.StatusUnauthorized))
-		return
+		return NewAppError("Usuario no autenticado", http.StatusUnauthorized)
 	}
 
 	user, err := h.userService.GetUserByID(c.Context(), userID)
 	if err != nil {
-		RespondError(c.RWriter, NewAppError("Usuario no encontrado", http.StatusNotFound))
-		return
+		return err
 	}
 
+	// Las fechas se serializan en RFC 3339 y UTC; last_login_at es null
+	// hasta el primer inicio de sesión
 	RespondJSON(c.RWriter, http.StatusOK, map[string]interface{}{
-		"user": map[string]interface{}{
-			"id":    user.ID,
-			"name":  user.Name,
-			"email": user.Email,
-		},
+		"user": user,
 	})
+	return nil
 }
@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// FileMailer guarda cada correo como un archivo .eml en un directorio, de
+// donde se puede leer en pruebas o abrir con un cliente de correo
+type FileMailer struct {
+	dir  string
+	from string
+	seq  atomic.Uint64
+}
+
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error al crear el directorio de correos: %w", err)
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	// El nombre ordena los archivos por fecha y el contador evita choques
+	// entre correos enviados en el mismo instante
+	name := fmt.Sprintf("%s-%04d-%s.eml",
+		time.Now().UTC().Format("20060102T150405.000000000"), m.seq.Add(1), safeName(msg.To))
+
+	if err := os.WriteFile(filepath.Join(m.dir, name), formatMessage(m.from, msg), 0o644); err != nil {
+		return fmt.Errorf("error al guardar el correo: %w", err)
+	}
+	return nil
+}
+
+// safeName deja solo caracteres seguros para un nombre de archivo
+func safeName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
@@ -0,0 +1,20 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// LogMailer escribe los correos en el log en lugar de enviarlos
+type LogMailer struct {
+	from string
+}
+
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{from: from}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("✉ Correo de %s para %s: %s\n%s", m.from, msg.To, msg.Subject, msg.Body)
+	return nil
+}
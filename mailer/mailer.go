@@ -0,0 +1,51 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/gopost-api/config"
+)
+
+// Message es un correo de texto plano
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer envía correos. Hay implementaciones SMTP para producción y de
+// archivo y log para desarrollo y pruebas, que no necesitan red.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Drivers de envío soportados en la variable MAILER
+const (
+	DriverLog  = "log"
+	DriverFile = "file"
+	DriverSMTP = "smtp"
+)
+
+// New crea el Mailer indicado por la configuración. MAILER no tiene valor
+// por defecto: los correos llevan tokens de un solo uso y el driver log los
+// deja escritos en el log, así que debe elegirse a propósito.
+func New(cfg *config.Config) (Mailer, error) {
+	switch cfg.Mailer {
+	case "":
+		return nil, fmt.Errorf("MAILER es obligatorio (log, file o smtp)")
+	case DriverLog:
+		log.Println("⚠ MAILER=log: los correos y sus tokens se escriben en el log; use smtp en producción")
+		return NewLogMailer(cfg.MailFrom), nil
+	case DriverFile:
+		return NewFileMailer(cfg.MailDir, cfg.MailFrom)
+	case DriverSMTP:
+		if cfg.SMTPHost == "" {
+			return nil, fmt.Errorf("MAILER=smtp requiere SMTP_HOST")
+		}
+		return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom), nil
+	default:
+		return nil, fmt.Errorf("MAILER %q no es válido (log, file o smtp)", cfg.Mailer)
+	}
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"testing"
+
+	"github.com/gopost-api/config"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Config
+		wantErr bool
+	}{
+		{name: "sin MAILER", cfg: config.Config{}, wantErr: true},
+		{name: "log", cfg: config.Config{Mailer: DriverLog}},
+		{name: "file", cfg: config.Config{Mailer: DriverFile, MailDir: t.TempDir()}},
+		{name: "smtp sin host", cfg: config.Config{Mailer: DriverSMTP}, wantErr: true},
+		{name: "smtp", cfg: config.Config{Mailer: DriverSMTP, SMTPHost: "smtp.example.com", SMTPPort: 587}},
+		{name: "desconocido", cfg: config.Config{Mailer: "sendmail"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := New(&tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("New() = %T, se esperaba un error", m)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+		})
+	}
+}
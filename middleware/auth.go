@@ -1,58 +1,41 @@
 package middleware
 
 import (
-	"fmt"
-	"net/http"
 	"strings"
 
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/gopost-api/config"
-	"github.com/gopost-api/handlers"
+	"github.com/gopost-api/apperrors"
 	"github.com/gopost-api/server"
+	"github.com/gopost-api/services"
 )
 
-func AuthMiddleware(next server.HandleFunc) server.HandleFunc {
-	return func(c *server.Context) {
-		authHeader := c.Request.Header.Get("Authorization")
-		if authHeader == "" {
-			handlers.RespondError(c.RWriter, handlers.NewAppError("Token no proporcionado", http.StatusUnauthorized))
-			return
-		}
-
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			handlers.RespondError(c.RWriter, handlers.NewAppError("Formato de token inválido", http.StatusUnauthorized))
-			return
-		}
-
-		tokenString := parts[1]
+// AuthMiddleware exige un access token válido y no revocado. Deja el ID del
+// usuario en el contexto y los claims completos bajo services.ClaimsKey.
+// Los rechazos se responden con el ErrorHandler de la App.
+func AuthMiddleware(tokens *services.TokenService) server.Middleware {
+	return func(next server.HandleFunc) server.HandleFunc {
+		return func(c *server.Context) {
+			authHeader := c.Request.Header.Get("Authorization")
+			if authHeader == "" {
+				c.Error(apperrors.Unauthorized("Token no proporcionado"))
+				return
+			}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("método de firma inesperado: %v", token.Header["alg"])
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				c.Error(apperrors.Unauthorized("Formato de token inválido"))
+				return
 			}
-			return []byte(config.AppConfig.JWTSecret), nil
-		})
 
-		if err != nil || !token.Valid {
-			handlers.RespondError(c.RWriter, handlers.NewAppError("Token inválido o expirado", http.StatusUnauthorized))
-			return
-		}
+			claims, err := tokens.Verify(c.Context(), parts[1])
+			if err != nil {
+				c.Error(err)
+				return
+			}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			handlers.RespondError(c.RWriter, handlers.NewAppError("Claims inválidos", http.StatusUnauthorized))
-			return
-		}
+			c.SetUserID(claims.UserID)
+			c.Set(services.ClaimsKey, claims)
 
-		userID, ok := claims["user_id"].(float64)
-		if !ok {
-			handlers.RespondError(c.RWriter, handlers.NewAppError("User ID no encontrado en el token", http.StatusUnauthorized))
-			return
+			next(c)
 		}
-
-		c.SetUserID(uint(userID))
-
-		next(c)
 	}
 }
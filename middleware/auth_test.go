@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopost-api/config"
+	"github.com/gopost-api/repositories/memory"
+	"github.com/gopost-api/server"
+	"github.com/gopost-api/services"
+)
+
+func newTestTokenService(t *testing.T) *services.TokenService {
+	t.Helper()
+	keys, err := services.LoadKeySet(&config.Config{JWTAlgorithm: "HS256", JWTSecret: strings.Repeat("k", 32)})
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+	return services.NewTokenService(memory.NewUserStore(), memory.NewRefreshTokenStore(), memory.NewRevocationStore(), keys, 15*time.Minute, 24*time.Hour)
+}
+
+func TestAuthMiddlewareRejectsThroughErrorHandler(t *testing.T) {
+	var handled []error
+	app := server.New(server.WithErrorHandler(func(c *server.Context, err error) {
+		handled = append(handled, err)
+		server.DefaultErrorHandler(c, err)
+	}))
+	app.Get("/privado", func(c *server.Context) {
+		c.JSON(http.StatusOK, map[string]string{"message": "ok"})
+	}, AuthMiddleware(newTestTokenService(t)))
+
+	tests := []struct {
+		name        string
+		header      string
+		wantMessage string
+	}{
+		{name: "sin header", header: "", wantMessage: "Token no proporcionado"},
+		{name: "sin Bearer", header: "Token abc", wantMessage: "Formato de token inválido"},
+		{name: "token inválido", header: "Bearer abc", wantMessage: "Token inválido o expirado"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled = nil
+			req := httptest.NewRequest(http.MethodGet, "/privado", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, se esperaba 401", rec.Code)
+			}
+			if len(handled) != 1 {
+				t.Fatalf("el ErrorHandler recibió %d errores, se esperaba 1", len(handled))
+			}
+			var body server.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("cuerpo no es un ErrorResponse: %q", rec.Body.String())
+			}
+			if body.Message != tt.wantMessage {
+				t.Errorf("message = %q, se esperaba %q", body.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gopost-api/server"
+)
+
+// statusRecorder envuelve el ResponseWriter para conocer el código de estado enviado
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// LoggerMiddleware registra el método, la ruta, el código de estado y la
+// duración de cada petición
+func LoggerMiddleware(next server.HandleFunc) server.HandleFunc {
+	return func(c *server.Context) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: c.RWriter, status: http.StatusOK}
+		c.RWriter = recorder
+
+		next(c)
+
+		log.Printf("%s %s %d %s", c.Request.Method, c.Request.URL.Path, recorder.status, time.Since(start))
+	}
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gopost-api/server"
+)
+
+// panicError transporta un panic ocurrido en otra goroutine junto con su
+// stack trace original, para que RecoveryMiddleware pueda registrarlo
+type panicError struct {
+	value interface{}
+	stack []byte
+}
+
+func (p *panicError) Error() string {
+	return fmt.Sprintf("%v", p.value)
+}
+
+// writeTracker envuelve el ResponseWriter para saber si el handler ya
+// empezó a responder
+type writeTracker struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *writeTracker) WriteHeader(code int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *writeTracker) Write(p []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(p)
+}
+
+// RecoveryMiddleware recupera los panics de los handlers, registra el stack
+// trace y responde con un 500 a través del ErrorHandler de la App en lugar
+// de cortar la conexión. Si el handler ya había empezado a responder, la
+// respuesta se aborta: un 500 a continuación la dejaría corrupta.
+func RecoveryMiddleware(next server.HandleFunc) server.HandleFunc {
+	return func(c *server.Context) {
+		tracker := &writeTracker{ResponseWriter: c.RWriter}
+		c.RWriter = tracker
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			// http.ErrAbortHandler se usa a propósito para abortar la respuesta
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			stack := debug.Stack()
+			if p, ok := rec.(*panicError); ok {
+				rec, stack = p.value, p.stack
+			}
+
+			log.Printf("panic recuperado en %s %s: %v\n%s", c.Request.Method, c.Request.URL.Path, rec, stack)
+			if tracker.written {
+				panic(http.ErrAbortHandler)
+			}
+			c.Error(server.NewHTTPError(http.StatusInternalServerError, "Error interno del servidor"))
+		}()
+
+		next(c)
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gopost-api/server"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       server.HandleFunc
+		customHandler bool
+		wantPanic     interface{}
+		wantLog       string
+	}{
+		{
+			name:    "panic en el handler",
+			handler: func(c *server.Context) { panic("boom") },
+			wantLog: "panic recuperado en GET /panic: boom",
+		},
+		{
+			name: "panic reenviado por TimeoutMiddleware",
+			handler: func(c *server.Context) {
+				panic(&panicError{value: "boom", stack: []byte("stack de la goroutine del handler")})
+			},
+			wantLog: "boom\nstack de la goroutine del handler",
+		},
+		{
+			name:          "ErrorHandler propio",
+			handler:       func(c *server.Context) { panic("boom") },
+			customHandler: true,
+		},
+		{
+			name:      "http.ErrAbortHandler se vuelve a lanzar",
+			handler:   func(c *server.Context) { panic(http.ErrAbortHandler) },
+			wantPanic: http.ErrAbortHandler,
+		},
+		{
+			name: "respuesta ya empezada se aborta",
+			handler: func(c *server.Context) {
+				c.RWriter.WriteHeader(http.StatusOK)
+				c.RWriter.Write([]byte(`{"data":`))
+				panic("boom")
+			},
+			wantPanic: http.ErrAbortHandler,
+			wantLog:   "panic recuperado en GET /panic: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			previous := log.Writer()
+			log.SetOutput(&logs)
+			t.Cleanup(func() { log.SetOutput(previous) })
+
+			var handled error
+			var options []server.Option
+			if tt.customHandler {
+				options = append(options, server.WithErrorHandler(func(c *server.Context, err error) {
+					handled = err
+					c.Status(http.StatusTeapot)
+				}))
+			}
+			app := server.New(options...)
+			app.Use(RecoveryMiddleware)
+			app.Get("/panic", tt.handler)
+
+			rec := httptest.NewRecorder()
+			got := func() (panicked interface{}) {
+				defer func() { panicked = recover() }()
+				app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+				return nil
+			}()
+
+			if got != tt.wantPanic {
+				t.Fatalf("panic = %v, se esperaba %v", got, tt.wantPanic)
+			}
+			if tt.wantLog != "" && !strings.Contains(logs.String(), tt.wantLog) {
+				t.Errorf("el log no contiene %q:\n%s", tt.wantLog, logs.String())
+			}
+			if tt.wantPanic != nil {
+				if strings.Contains(rec.Body.String(), "Error interno") {
+					t.Errorf("no debía responder un 500 tras el panic, cuerpo %q", rec.Body.String())
+				}
+				return
+			}
+
+			if tt.customHandler {
+				if server.StatusFor(handled) != http.StatusInternalServerError || rec.Code != http.StatusTeapot {
+					t.Fatalf("el ErrorHandler propio recibió %v y respondió %d", handled, rec.Code)
+				}
+				return
+			}
+
+			if rec.Code != http.StatusInternalServerError {
+				t.Fatalf("status = %d, se esperaba %d", rec.Code, http.StatusInternalServerError)
+			}
+			var body server.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Message != "Error interno del servidor" {
+				t.Errorf("cuerpo = %q, se esperaba el ErrorResponse del 500", rec.Body.String())
+			}
+		})
+	}
+}
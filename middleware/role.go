@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/server"
+	"github.com/gopost-api/services"
+)
+
+// RequireRole deja pasar solo a los usuarios con alguno de los roles
+// indicados. Debe ir después de AuthMiddleware, que es quien deja los
+// claims del token en el contexto.
+func RequireRole(roles ...models.Role) server.Middleware {
+	allowed := make(map[models.Role]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(next server.HandleFunc) server.HandleFunc {
+		return func(c *server.Context) {
+			value, _ := c.Get(services.ClaimsKey)
+			claims, ok := value.(*services.AccessClaims)
+			if !ok {
+				c.Error(apperrors.Unauthorized("Usuario no autenticado"))
+				return
+			}
+
+			if !allowed[claims.Role] {
+				c.Error(apperrors.Forbidden("No tienes permiso para acceder a este recurso"))
+				return
+			}
+
+			next(c)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/server"
+	"github.com/gopost-api/services"
+)
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		claims     *services.AccessClaims
+		wantStatus int
+	}{
+		{name: "sin claims", claims: nil, wantStatus: http.StatusUnauthorized},
+		{name: "rol no permitido", claims: &services.AccessClaims{UserID: 1, Role: models.RoleUser}, wantStatus: http.StatusForbidden},
+		{name: "rol permitido", claims: &services.AccessClaims{UserID: 1, Role: models.RoleAdmin}, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handled error
+			app := server.New(server.WithErrorHandler(func(c *server.Context, err error) {
+				handled = err
+				server.DefaultErrorHandler(c, err)
+			}))
+			// Simula a AuthMiddleware dejando los claims en el contexto
+			withClaims := func(next server.HandleFunc) server.HandleFunc {
+				return func(c *server.Context) {
+					if tt.claims != nil {
+						c.Set(services.ClaimsKey, tt.claims)
+					}
+					next(c)
+				}
+			}
+			app.Get("/admin", func(c *server.Context) {
+				c.JSON(http.StatusOK, map[string]string{"message": "ok"})
+			}, withClaims, RequireRole(models.RoleAdmin, models.RoleModerator))
+
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, se esperaba %d", rec.Code, tt.wantStatus)
+			}
+			if got := server.StatusFor(handled); tt.wantStatus != http.StatusOK && got != tt.wantStatus {
+				t.Errorf("el ErrorHandler recibió %v (%d), se esperaba %d", handled, got, tt.wantStatus)
+			}
+		})
+	}
+}
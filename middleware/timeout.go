@@ -1,16 +1,99 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"log"
 	"net/http"
+	"runtime/debug"
+	"sync"
 	"time"
 
-	"github.com/gopost-api/handlers"
 	"github.com/gopost-api/server"
 )
 
+// timeoutWriter acumula la respuesta del handler en memoria. Solo se copia
+// al ResponseWriter real si el handler termina antes del timeout; si el
+// timeout se agota primero, el buffer se descarta y las escrituras tardías
+// se ignoran devolviendo http.ErrHandlerTimeout.
+type timeoutWriter struct {
+	mu          sync.Mutex
+	header      http.Header
+	buf         bytes.Buffer
+	code        int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func newTimeoutWriter() *timeoutWriter {
+	return &timeoutWriter{
+		header: make(http.Header),
+		code:   http.StatusOK,
+	}
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.wroteHeader = true
+	return tw.buf.Write(p)
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	tw.code = code
+}
+
+// flush copia la respuesta acumulada al ResponseWriter real
+func (tw *timeoutWriter) flush(w http.ResponseWriter) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	dst := w.Header()
+	for key, values := range tw.header {
+		dst[key] = values
+	}
+	w.WriteHeader(tw.code)
+	w.Write(tw.buf.Bytes())
+}
+
+// markTimedOut descarta el buffer e impide nuevas escrituras
+func (tw *timeoutWriter) markTimedOut() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	tw.timedOut = true
+	tw.buf.Reset()
+}
+
+func (tw *timeoutWriter) isTimedOut() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.timedOut
+}
+
 // TimeoutMiddleware agrega un timeout específico a las peticiones
 // Si la petición tarda más del timeout especificado, se cancela automáticamente
+// y se responde con 503 Service Unavailable a través del ErrorHandler de la
+// App. El handler corre en otra goroutine sobre una copia del Context que
+// escribe en un buffer protegido, por lo que nunca compite con la respuesta
+// de timeout ni con los middlewares exteriores que leen el Context original.
+// Si el cliente se desconecta antes, la petición se abandona sin responder.
 func TimeoutMiddleware(timeout time.Duration) func(server.HandleFunc) server.HandleFunc {
 	return func(next server.HandleFunc) server.HandleFunc {
 		return func(c *server.Context) {
@@ -18,29 +101,62 @@ func TimeoutMiddleware(timeout time.Duration) func(server.HandleFunc) server.Han
 			ctx, cancel := context.WithTimeout(c.Ctx, timeout)
 			defer cancel()
 
-			// Actualizar el context en el Context personalizado
-			c.Ctx = ctx
+			// El handler recibe su propio Context: el router reasigna su
+			// Request y, tras el timeout, puede seguir escribiendo en él
+			tw := newTimeoutWriter()
+			inner := c.Derive(ctx, tw)
 
 			// Canal para saber cuándo terminó el handler
 			done := make(chan bool, 1)
+			// Canal para propagar un panic del handler a esta goroutine
+			panicked := make(chan *panicError, 1)
 
-			// Ejecutar el handler en una goroutine
+			// Ejecutar el handler en una goroutine; desde aquí solo se usa inner
+			method, path := c.Request.Method, c.Request.URL.Path
 			go func() {
-				next(c)
+				defer func() {
+					if rec := recover(); rec != nil {
+						p := &panicError{value: rec, stack: debug.Stack()}
+						if tw.isTimedOut() {
+							// Ya nadie espera la respuesta: solo queda registrarlo
+							log.Printf("panic tras el timeout en %s %s: %v\n%s", method, path, p.value, p.stack)
+							return
+						}
+						panicked <- p
+					}
+				}()
+				next(inner)
 				done <- true
 			}()
 
 			// Esperar a que termine o se agote el timeout
 			select {
 			case <-done:
-				// El handler terminó correctamente
+				// El handler terminó correctamente: enviar la respuesta acumulada
+				tw.flush(c.RWriter)
 				return
+			case p := <-panicked:
+				// Relanzar el panic para que lo recupere RecoveryMiddleware
+				panic(p)
 			case <-ctx.Done():
-				// Se agotó el timeout
-				handlers.RespondError(c.RWriter, handlers.NewAppError(
-					"La operación tardó demasiado tiempo",
-					http.StatusRequestTimeout,
-				))
+				// Se agotó el timeout o el cliente se desconectó: descartar lo
+				// que haya escrito el handler e ignorar sus escrituras tardías
+				tw.markTimedOut()
+				inner.MarkTimedOut()
+
+				// El handler pudo entrar en panic justo antes de marcar el timeout
+				select {
+				case p := <-panicked:
+					log.Printf("panic tras el timeout en %s %s: %v\n%s", method, path, p.value, p.stack)
+				default:
+				}
+
+				// Si el cliente cerró la conexión no hay a quién responder
+				if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return
+				}
+
+				c.Error(server.NewHTTPError(http.StatusServiceUnavailable, "La operación tardó demasiado tiempo"))
 				return
 			}
 		}
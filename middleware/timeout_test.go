@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gopost-api/server"
+)
+
+// serve ejecuta el middleware sobre un Context armado a mano, como lo hace
+// server.App en cada petición
+func serve(t *testing.T, ctx context.Context, timeout time.Duration, handler server.HandleFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	c := &server.Context{RWriter: rec, Request: req, Ctx: ctx}
+	TimeoutMiddleware(timeout)(handler)(c)
+	return rec
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    server.HandleFunc
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "el handler termina a tiempo",
+			handler: func(c *server.Context) {
+				c.JSON(http.StatusCreated, map[string]string{"message": "ok"})
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   "ok",
+		},
+		{
+			name: "el handler supera el timeout",
+			handler: func(c *server.Context) {
+				<-c.Context().Done()
+				// Esta escritura tardía debe descartarse
+				c.RWriter.Write([]byte("tarde"))
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "La operación tardó demasiado tiempo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, context.Background(), 50*time.Millisecond, tt.handler)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, se esperaba %d", rec.Code, tt.wantStatus)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("cuerpo no es JSON: %q", rec.Body.String())
+			}
+			if body["message"] != tt.wantBody {
+				t.Errorf("message = %v, se esperaba %q", body["message"], tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestTimeoutMiddlewareClientDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	handlerDone := make(chan struct{})
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	rec := serve(t, ctx, time.Second, func(c *server.Context) {
+		defer close(handlerDone)
+		<-c.Context().Done()
+		c.JSON(http.StatusOK, map[string]string{"message": "tarde"})
+	})
+	<-handlerDone
+
+	if rec.Body.Len() != 0 || rec.Header().Get("Content-Type") != "" {
+		t.Fatalf("no debía responder al cliente desconectado, se escribió %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestTimeoutMiddlewarePropagatesPanic(t *testing.T) {
+	defer func() {
+		rec := recover()
+		p, ok := rec.(*panicError)
+		if !ok || p.value != "boom" {
+			t.Fatalf("se esperaba el panic del handler, se obtuvo %#v", rec)
+		}
+	}()
+
+	serve(t, context.Background(), time.Second, func(c *server.Context) {
+		panic("boom")
+	})
+}
+
+// Con la misma cadena global que cmd/api, el handler que sigue corriendo
+// tras el timeout no debe compartir el Context que leen LoggerMiddleware y
+// RecoveryMiddleware. Ejecutar con -race.
+func TestTimeoutMiddlewareGlobalStack(t *testing.T) {
+	handlerDone := make(chan struct{})
+	app := server.New(server.WithErrorHandler(func(c *server.Context, err error) {
+		c.JSON(server.StatusFor(err), map[string]string{"message": "desde el ErrorHandler: " + err.Error()})
+	}))
+	app.Use(LoggerMiddleware, RecoveryMiddleware, TimeoutMiddleware(50*time.Millisecond))
+	app.Get("/lento/{id}", func(c *server.Context) {
+		defer close(handlerDone)
+		time.Sleep(200 * time.Millisecond)
+		c.Set("id", c.Request.PathValue("id"))
+		c.JSON(http.StatusOK, map[string]string{"message": "tarde"})
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/lento/7", nil))
+	<-handlerDone
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, se esperaba %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("cuerpo no es JSON: %q", rec.Body.String())
+	}
+	if want := "desde el ErrorHandler: La operación tardó demasiado tiempo"; body["message"] != want {
+		t.Errorf("message = %q, se esperaba %q", body["message"], want)
+	}
+}
@@ -1,10 +1,12 @@
 package models
 
+import "time"
+
 type Post struct {
-	ID      uint   `json:"id"`
-	UserID  uint   `json:"user_id"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"user_id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
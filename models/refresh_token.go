@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// RefreshToken es un refresh token emitido a un usuario. Solo se guarda el
+// hash del token; FamilyID agrupa todos los tokens obtenidos por rotación
+// a partir del mismo inicio de sesión.
+type RefreshToken struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id"`
+	FamilyID  string     `json:"family_id"`
+	TokenHash string     `json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// Role es el rol de un usuario, que determina qué puede hacer en la API
+type Role string
+
+const (
+	// RoleUser es el rol por defecto: solo puede modificar lo propio
+	RoleUser Role = "user"
+	// RoleModerator puede editar y eliminar posts de cualquier usuario
+	RoleModerator Role = "moderator"
+	// RoleAdmin puede además administrar los roles de los usuarios
+	RoleAdmin Role = "admin"
+)
+
+// Valid indica si el rol es uno de los conocidos
+func (r Role) Valid() bool {
+	switch r {
+	case RoleUser, RoleModerator, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
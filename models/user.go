@@ -1,8 +1,23 @@
 package models
 
+import "time"
+
+// DeletedUserEmail identifica la cuenta que conserva los posts de los
+// usuarios eliminados cuando se anonimizan. La crea una migración.
+const DeletedUserEmail = "deleted-user@gopost.invalid"
+
 type User struct {
-	ID       uint   `json:"id"`
-	Name     string `json:"name"`
-	Email    string `json:"email" gorm:"unique"`
-	Password string `json:"-"`
-}
\ No newline at end of file
+	ID            uint       `json:"id"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email" gorm:"unique"`
+	EmailVerified bool       `json:"email_verified"`
+	Password      string     `json:"-"`
+	Role          Role       `json:"role"`
+	Bio           string     `json:"bio"`
+	AvatarURL     string     `json:"avatar_url"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	LastLoginAt   *time.Time `json:"last_login_at"`
+	// DeletionRequestedAt indica desde cuándo la cuenta espera ser borrada
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at"`
+}
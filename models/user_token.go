@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Propósitos de los tokens de un solo uso que se envían por email
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailChange       = "email_change"
+)
+
+// UserToken es un token de un solo uso enviado al usuario por email. Solo
+// se guarda su hash; Email es la dirección a la que se envió.
+type UserToken struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id"`
+	Purpose   string     `json:"purpose"`
+	TokenHash string     `json:"-"`
+	Email     string     `json:"email"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+}
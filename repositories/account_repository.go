@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/models"
+)
+
+// PostsOnDelete indica qué pasa con los posts de una cuenta eliminada
+type PostsOnDelete string
+
+const (
+	// PostsDelete borra los posts junto con la cuenta
+	PostsDelete PostsOnDelete = "delete"
+	// PostsAnonymize reasigna los posts a la cuenta "Usuario eliminado"
+	PostsAnonymize PostsOnDelete = "anonymize"
+)
+
+func (p PostsOnDelete) Valid() bool {
+	return p == PostsDelete || p == PostsAnonymize
+}
+
+// deletedUserPassword es la contraseña de la cuenta "Usuario eliminado";
+// junto con el email la distingue de una cuenta registrada con esa dirección
+const deletedUserPassword = "!"
+
+// AccountRepository borra cuentas. Los refresh tokens y los tokens enviados
+// por email se borran en cascada con el usuario. Las revocaciones de tokens
+// se conservan: los access tokens ya emitidos siguen revocados hasta expirar.
+type AccountRepository struct {
+	db      *sql.DB
+	dialect database.Dialect
+}
+
+func NewAccountRepository(db *sql.DB, dialect database.Dialect) *AccountRepository {
+	return &AccountRepository{db: db, dialect: dialect}
+}
+
+func (r *AccountRepository) PurgeUser(ctx context.Context, id uint, before time.Time, posts PostsOnDelete) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("error al iniciar la transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	// El usuario pudo cancelar la eliminación desde que se lo eligió
+	query := "SELECT id FROM users WHERE id = ? AND deletion_requested_at IS NOT NULL AND deletion_requested_at <= ?"
+	if r.dialect.Name() != database.DriverSQLite {
+		query += " FOR UPDATE"
+	}
+	var pending uint
+	err = tx.QueryRowContext(ctx, r.dialect.Rebind(query), id, r.dialect.TimeValue(before)).Scan(&pending)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error al buscar la cuenta a eliminar: %w", err)
+	}
+
+	switch posts {
+	case PostsAnonymize:
+		var deletedUserID uint
+		query := "SELECT id FROM users WHERE email = ? AND password = ?"
+		err := tx.QueryRowContext(ctx, r.dialect.Rebind(query), models.DeletedUserEmail, deletedUserPassword).Scan(&deletedUserID)
+		if err == sql.ErrNoRows {
+			return false, fmt.Errorf("no existe la cuenta %s; aplica las migraciones pendientes", models.DeletedUserEmail)
+		}
+		if err != nil {
+			return false, fmt.Errorf("error al buscar la cuenta de usuario eliminado: %w", err)
+		}
+
+		query = "UPDATE posts SET user_id = ? WHERE user_id = ?"
+		if _, err := tx.ExecContext(ctx, r.dialect.Rebind(query), deletedUserID, id); err != nil {
+			return false, fmt.Errorf("error al anonimizar los posts: %w", err)
+		}
+
+	case PostsDelete:
+		query := "DELETE FROM posts WHERE user_id = ?"
+		if _, err := tx.ExecContext(ctx, r.dialect.Rebind(query), id); err != nil {
+			return false, fmt.Errorf("error al eliminar los posts: %w", err)
+		}
+
+	default:
+		return false, apperrors.Validation(fmt.Sprintf("modo de eliminación desconocido: %s", posts))
+	}
+
+	query = "DELETE FROM users WHERE id = ?"
+	if _, err := tx.ExecContext(ctx, r.dialect.Rebind(query), id); err != nil {
+		return false, fmt.Errorf("error al eliminar el usuario: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error al confirmar la eliminación: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,8 @@
+//go:build !cgo
+
+package repositories
+
+// isSQLiteUniqueViolation siempre es false: sin cgo no se incluye SQLite
+func isSQLiteUniqueViolation(err error) bool {
+	return false
+}
@@ -0,0 +1,21 @@
+//go:build cgo
+
+package repositories
+
+import (
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// isSQLiteUniqueViolation reconoce las claves duplicadas de SQLite. El
+// driver requiere cgo; sin él SQLite no está disponible y nunca hay errores
+// suyos que reconocer.
+func isSQLiteUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique ||
+			sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/repositories"
+)
+
+// AccountStore borra cuentas de un UserStore, sus posts de un PostStore y
+// sus refresh tokens y tokens por email, con la misma semántica que
+// repositories.AccountRepository: las revocaciones de tokens se conservan.
+// Toma todos los bloqueos para que el borrado sea atómico.
+type AccountStore struct {
+	users         *UserStore
+	posts         *PostStore
+	refreshTokens *RefreshTokenStore
+	userTokens    *UserTokenStore
+}
+
+var _ repositories.AccountStore = (*AccountStore)(nil)
+
+func NewAccountStore(users *UserStore, posts *PostStore, refreshTokens *RefreshTokenStore, userTokens *UserTokenStore) *AccountStore {
+	return &AccountStore{users: users, posts: posts, refreshTokens: refreshTokens, userTokens: userTokens}
+}
+
+func (s *AccountStore) PurgeUser(ctx context.Context, id uint, before time.Time, posts repositories.PostsOnDelete) (bool, error) {
+	if !posts.Valid() {
+		return false, apperrors.Validation(fmt.Sprintf("modo de eliminación desconocido: %s", posts))
+	}
+
+	s.users.mu.Lock()
+	defer s.users.mu.Unlock()
+	s.posts.mu.Lock()
+	defer s.posts.mu.Unlock()
+	s.refreshTokens.mu.Lock()
+	defer s.refreshTokens.mu.Unlock()
+	s.userTokens.mu.Lock()
+	defer s.userTokens.mu.Unlock()
+
+	user, ok := s.users.users[id]
+	if !ok || !pendingDeletion(user, before) {
+		return false, nil
+	}
+
+	var deletedUserID uint
+	if posts == repositories.PostsAnonymize {
+		var err error
+		if deletedUserID, err = s.deletedUser(); err != nil {
+			return false, err
+		}
+	}
+	for postID, post := range s.posts.posts {
+		if post.UserID != id {
+			continue
+		}
+		if posts == repositories.PostsDelete {
+			delete(s.posts.posts, postID)
+			continue
+		}
+		post.UserID = deletedUserID
+		s.posts.posts[postID] = post
+	}
+
+	// En la base de datos se borran en cascada con el usuario
+	for tokenID, token := range s.refreshTokens.tokens {
+		if token.UserID == id {
+			delete(s.refreshTokens.byHash, token.TokenHash)
+			delete(s.refreshTokens.tokens, tokenID)
+		}
+	}
+	for tokenID, token := range s.userTokens.tokens {
+		if token.UserID == id {
+			delete(s.userTokens.byHash, token.TokenHash)
+			delete(s.userTokens.tokens, tokenID)
+		}
+	}
+
+	delete(s.users.byEmail, emailKey(user.Email))
+	delete(s.users.users, id)
+	return true, nil
+}
+
+// deletedUser devuelve el id de la cuenta "Usuario eliminado" y la crea si
+// no existe, como lo hace la migración en la base de datos. Requiere el
+// bloqueo de usuarios.
+func (s *AccountStore) deletedUser() (uint, error) {
+	if id, ok := s.users.byEmail[emailKey(models.DeletedUserEmail)]; ok {
+		if s.users.users[id].Password != "!" {
+			return 0, fmt.Errorf("el email %s pertenece a una cuenta registrada", models.DeletedUserEmail)
+		}
+		return id, nil
+	}
+
+	now := database.Now()
+	user := models.User{
+		ID:            s.users.nextID,
+		Name:          "Usuario eliminado",
+		Email:         models.DeletedUserEmail,
+		EmailVerified: true,
+		Password:      "!",
+		Role:          models.RoleUser,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	s.users.users[user.ID] = user
+	s.users.byEmail[emailKey(user.Email)] = user.ID
+	s.users.nextID++
+	return user.ID, nil
+}
@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/repositories"
+)
+
+// PostStore guarda los posts en memoria con la misma semántica que
+// repositories.PostRepository. Es seguro para uso concurrente.
+type PostStore struct {
+	mu     sync.RWMutex
+	posts  map[uint]models.Post
+	nextID uint
+}
+
+var _ repositories.PostStore = (*PostStore)(nil)
+
+func NewPostStore() *PostStore {
+	return &PostStore{
+		posts:  make(map[uint]models.Post),
+		nextID: 1,
+	}
+}
+
+func (s *PostStore) Create(ctx context.Context, post *models.Post) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := database.Now()
+	stored := *post
+	stored.ID = s.nextID
+	stored.CreatedAt = now
+	stored.UpdatedAt = now
+	s.posts[stored.ID] = stored
+	s.nextID++
+
+	post.ID = stored.ID
+	post.CreatedAt = now
+	post.UpdatedAt = now
+	return nil
+}
+
+// List aplica filtros, orden y paginación igual que la consulta SQL
+func (s *PostStore) List(ctx context.Context, q repositories.PostQuery) ([]models.Post, int, error) {
+	q = q.Normalize()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	posts := []models.Post{}
+	for _, post := range s.posts {
+		if matches(post, q) {
+			posts = append(posts, post)
+		}
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		order := compareField(posts[i], posts[j], q.Sort.Field)
+		if order == 0 {
+			order = compareField(posts[i], posts[j], "id")
+		}
+		if q.Sort.Desc {
+			return order > 0
+		}
+		return order < 0
+	})
+
+	total := len(posts)
+	if q.Offset >= total {
+		return []models.Post{}, total, nil
+	}
+	end := q.Offset + q.Limit
+	if end > total {
+		end = total
+	}
+	return posts[q.Offset:end], total, nil
+}
+
+// Feed aplica la misma condición keyset que la consulta SQL
+func (s *PostStore) Feed(ctx context.Context, q repositories.PostFeedQuery) ([]models.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var after time.Time
+	if q.After != nil {
+		after = q.After.CreatedAt.UTC().Truncate(time.Second)
+	}
+
+	posts := []models.Post{}
+	for _, post := range s.posts {
+		if q.UserID != 0 && post.UserID != q.UserID {
+			continue
+		}
+		if q.After != nil && !(post.CreatedAt.Before(after) || (post.CreatedAt.Equal(after) && post.ID < q.After.ID)) {
+			continue
+		}
+		posts = append(posts, post)
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		if !posts[i].CreatedAt.Equal(posts[j].CreatedAt) {
+			return posts[i].CreatedAt.After(posts[j].CreatedAt)
+		}
+		return posts[i].ID > posts[j].ID
+	})
+
+	if len(posts) > q.Limit {
+		posts = posts[:q.Limit]
+	}
+	return posts, nil
+}
+
+// Search puntúa igual que la búsqueda por LIKE: todas las palabras deben
+// aparecer y las coincidencias en el título valen doble
+func (s *PostStore) Search(ctx context.Context, q repositories.PostSearchQuery) ([]repositories.PostSearchHit, int, error) {
+	terms := repositories.SearchTerms(q.Text)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	hits := []repositories.PostSearchHit{}
+	for _, post := range s.posts {
+		title, content := strings.ToLower(post.Title), strings.ToLower(post.Content)
+		score := 0.0
+		for _, term := range terms {
+			inTitle, inContent := strings.Contains(title, term), strings.Contains(content, term)
+			if !inTitle && !inContent {
+				score = 0
+				break
+			}
+			if inTitle {
+				score += 2
+			}
+			if inContent {
+				score++
+			}
+		}
+		if score > 0 {
+			hits = append(hits, repositories.PostSearchHit{Post: post, Score: score})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if !a.Post.CreatedAt.Equal(b.Post.CreatedAt) {
+			return a.Post.CreatedAt.After(b.Post.CreatedAt)
+		}
+		return a.Post.ID > b.Post.ID
+	})
+
+	total := len(hits)
+	if q.Offset >= total {
+		return []repositories.PostSearchHit{}, total, nil
+	}
+	end := q.Offset + q.Limit
+	if end > total {
+		end = total
+	}
+	return hits[q.Offset:end], total, nil
+}
+
+func (s *PostStore) FindByID(ctx context.Context, id uint) (*models.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	post, ok := s.posts[id]
+	if !ok {
+		return nil, apperrors.NotFound("post no encontrado")
+	}
+	return &post, nil
+}
+
+func (s *PostStore) Update(ctx context.Context, post *models.Post) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.posts[post.ID]
+	if !ok {
+		return apperrors.NotFound("post no encontrado")
+	}
+	stored.Title = post.Title
+	stored.Content = post.Content
+	stored.UpdatedAt = database.Now()
+	s.posts[post.ID] = stored
+
+	post.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+func (s *PostStore) Delete(ctx context.Context, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.posts[id]; !ok {
+		return apperrors.NotFound("post no encontrado")
+	}
+	delete(s.posts, id)
+	return nil
+}
+
+// matches indica si el post cumple los filtros de la consulta
+func matches(post models.Post, q repositories.PostQuery) bool {
+	if q.UserID != 0 && post.UserID != q.UserID {
+		return false
+	}
+	if q.CreatedAfter != nil && !post.CreatedAt.After(q.CreatedAfter.UTC().Truncate(time.Second)) {
+		return false
+	}
+	if q.CreatedBefore != nil && !post.CreatedAt.Before(q.CreatedBefore.UTC().Truncate(time.Second)) {
+		return false
+	}
+	return true
+}
+
+// compareField compara dos posts por el campo de ordenamiento y devuelve
+// -1, 0 o 1 como time.Time.Compare
+func compareField(a, b models.Post, field string) int {
+	switch field {
+	case "updated_at":
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	case "title":
+		return strings.Compare(a.Title, b.Title)
+	case "id":
+		return cmp.Compare(a.ID, b.ID)
+	default:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}
+}
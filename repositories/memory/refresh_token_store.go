@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/repositories"
+)
+
+// RefreshTokenStore guarda los refresh tokens en memoria con la misma
+// semántica que repositories.RefreshTokenRepository. Es seguro para uso
+// concurrente.
+type RefreshTokenStore struct {
+	mu     sync.Mutex
+	tokens map[uint]models.RefreshToken
+	byHash map[string]uint
+	nextID uint
+}
+
+var _ repositories.RefreshTokenStore = (*RefreshTokenStore)(nil)
+
+func NewRefreshTokenStore() *RefreshTokenStore {
+	return &RefreshTokenStore{
+		tokens: make(map[uint]models.RefreshToken),
+		byHash: make(map[string]uint),
+		nextID: 1,
+	}
+}
+
+func (s *RefreshTokenStore) Create(ctx context.Context, token *models.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.byHash[token.TokenHash]; exists {
+		return apperrors.Conflict("refresh token duplicado")
+	}
+
+	stored := *token
+	stored.ID = s.nextID
+	stored.CreatedAt = database.Now()
+	s.tokens[stored.ID] = stored
+	s.byHash[stored.TokenHash] = stored.ID
+	s.nextID++
+
+	token.ID = stored.ID
+	token.CreatedAt = stored.CreatedAt
+	return nil
+}
+
+func (s *RefreshTokenStore) FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.byHash[hash]
+	if !ok {
+		return nil, apperrors.NotFound("refresh token no encontrado")
+	}
+	token := s.tokens[id]
+	return &token, nil
+}
+
+func (s *RefreshTokenStore) MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[id]
+	if !ok || token.UsedAt != nil || token.RevokedAt != nil {
+		return false, nil
+	}
+	at = at.UTC()
+	token.UsedAt = &at
+	s.tokens[id] = token
+	return true, nil
+}
+
+func (s *RefreshTokenStore) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at = at.UTC()
+	for id, token := range s.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+			s.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+func (s *RefreshTokenStore) RevokeUser(ctx context.Context, userID uint, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at = at.UTC()
+	for id, token := range s.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+			s.tokens[id] = token
+		}
+	}
+	return nil
+}
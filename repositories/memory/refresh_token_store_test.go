@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopost-api/models"
+)
+
+func TestRefreshTokenStoreMarkUsedAndRevokeFamily(t *testing.T) {
+	ctx := context.Background()
+	store := NewRefreshTokenStore()
+	now := time.Now().UTC()
+
+	tokens := map[string]*models.RefreshToken{
+		"a1": {UserID: 1, FamilyID: "a", TokenHash: "a1", ExpiresAt: now.Add(time.Hour)},
+		"a2": {UserID: 1, FamilyID: "a", TokenHash: "a2", ExpiresAt: now.Add(time.Hour)},
+		"b1": {UserID: 1, FamilyID: "b", TokenHash: "b1", ExpiresAt: now.Add(time.Hour)},
+	}
+	for _, hash := range []string{"a1", "a2", "b1"} {
+		if err := store.Create(ctx, tokens[hash]); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	if err := store.RevokeFamily(ctx, "a", now); err != nil {
+		t.Fatalf("RevokeFamily: %v", err)
+	}
+
+	steps := []struct {
+		name string
+		hash string
+		want bool
+	}{
+		{name: "familia revocada", hash: "a1", want: false},
+		{name: "otra familia", hash: "b1", want: true},
+		{name: "ya usado", hash: "b1", want: false},
+	}
+	for _, step := range steps {
+		used, err := store.MarkUsed(ctx, tokens[step.hash].ID, now)
+		if err != nil {
+			t.Fatalf("%s: MarkUsed: %v", step.name, err)
+		}
+		if used != step.want {
+			t.Errorf("%s: MarkUsed() = %t, se esperaba %t", step.name, used, step.want)
+		}
+	}
+
+	for hash, wantRevoked := range map[string]bool{"a1": true, "a2": true, "b1": false} {
+		stored, err := store.FindByHash(ctx, hash)
+		if err != nil {
+			t.Fatalf("FindByHash: %v", err)
+		}
+		if (stored.RevokedAt != nil) != wantRevoked {
+			t.Errorf("token %s: RevokedAt = %v, se esperaba revocado = %t", hash, stored.RevokedAt, wantRevoked)
+		}
+	}
+	if _, err := store.MarkUsed(ctx, 99, now); err != nil {
+		t.Errorf("MarkUsed de un token inexistente: %v", err)
+	}
+}
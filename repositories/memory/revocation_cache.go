@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gopost-api/repositories"
+)
+
+// RevocationCache agrega una caché en memoria delante de otro
+// RevocationStore para no consultar la base de datos en cada petición
+// autenticada.
+//
+// Las revocaciones hechas a través de la caché se ven de inmediato. Las
+// hechas por otra instancia de la API se ven, como máximo, después de ttl:
+// ese es el tiempo que se recuerda una respuesta "no revocado".
+type RevocationCache struct {
+	next repositories.RevocationStore
+	ttl  time.Duration
+
+	mu        sync.Mutex
+	tokens    map[string]cachedRevocation
+	cutoffs   map[uint]cachedCutoff
+	lastSweep time.Time
+}
+
+type cachedRevocation struct {
+	revoked bool
+	until   time.Time
+}
+
+type cachedCutoff struct {
+	before time.Time
+	until  time.Time
+}
+
+var _ repositories.RevocationStore = (*RevocationCache)(nil)
+
+func NewRevocationCache(next repositories.RevocationStore, ttl time.Duration) *RevocationCache {
+	return &RevocationCache{
+		next:    next,
+		ttl:     ttl,
+		tokens:  make(map[string]cachedRevocation),
+		cutoffs: make(map[uint]cachedCutoff),
+	}
+}
+
+func (c *RevocationCache) RevokeToken(ctx context.Context, jti string, userID uint, expiresAt time.Time) error {
+	if err := c.next.RevokeToken(ctx, jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Una revocación no se deshace: se recuerda hasta que el token expira
+	c.tokens[jti] = cachedRevocation{revoked: true, until: expiresAt}
+	return nil
+}
+
+func (c *RevocationCache) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.tokens[jti]
+	c.mu.Unlock()
+	if ok && now.Before(entry.until) {
+		return entry.revoked, nil
+	}
+
+	revoked, err := c.next.IsTokenRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sweep(now)
+	// Un RevokeToken concurrente pudo guardar la revocación mientras se
+	// consultaba el store: la lectura anterior no debe pisarla
+	if entry, ok := c.tokens[jti]; ok && entry.revoked && now.Before(entry.until) {
+		return true, nil
+	}
+	c.tokens[jti] = cachedRevocation{revoked: revoked, until: now.Add(c.ttl)}
+	return revoked, nil
+}
+
+func (c *RevocationCache) RevokeUserTokens(ctx context.Context, userID uint, before time.Time) error {
+	if err := c.next.RevokeUserTokens(ctx, userID, before); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Un corte más reciente guardado en paralelo no debe pisarse: el store
+	// conserva ese y la caché no puede quedar por detrás
+	if entry, ok := c.cutoffs[userID]; ok && entry.before.After(before) {
+		return nil
+	}
+	c.cutoffs[userID] = cachedCutoff{before: before.UTC(), until: time.Now().Add(c.ttl)}
+	return nil
+}
+
+func (c *RevocationCache) UserTokensRevokedBefore(ctx context.Context, userID uint) (time.Time, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.cutoffs[userID]
+	c.mu.Unlock()
+	if ok && now.Before(entry.until) {
+		return entry.before, nil
+	}
+
+	before, err := c.next.UserTokensRevokedBefore(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sweep(now)
+	// Igual que con los tokens: se conserva el corte más reciente
+	if entry, ok := c.cutoffs[userID]; ok && entry.before.After(before) && now.Before(entry.until) {
+		return entry.before, nil
+	}
+	c.cutoffs[userID] = cachedCutoff{before: before, until: now.Add(c.ttl)}
+	return before, nil
+}
+
+// DeleteExpired borra las revocaciones expiradas del store y descarta de
+// la caché las entradas vencidas
+func (c *RevocationCache) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	deleted, err := c.next.DeleteExpired(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeExpired(now)
+	return deleted, nil
+}
+
+// sweep descarta las entradas vencidas como mucho una vez por ttl, para
+// que la caché no crezca con tokens que ya no se presentan
+func (c *RevocationCache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.removeExpired(now)
+}
+
+func (c *RevocationCache) removeExpired(now time.Time) {
+	c.lastSweep = now
+
+	for jti, entry := range c.tokens {
+		if !now.Before(entry.until) {
+			delete(c.tokens, jti)
+		}
+	}
+	for userID, entry := range c.cutoffs {
+		if !now.Before(entry.until) {
+			delete(c.cutoffs, userID)
+		}
+	}
+}
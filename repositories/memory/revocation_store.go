@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gopost-api/repositories"
+)
+
+// RevocationStore guarda las revocaciones en memoria con la misma
+// semántica que repositories.RevocationRepository. Los jti revocados se
+// descartan al expirar el token. Es seguro para uso concurrente.
+type RevocationStore struct {
+	mu      sync.Mutex
+	tokens  map[string]time.Time
+	cutoffs map[uint]time.Time
+}
+
+var _ repositories.RevocationStore = (*RevocationStore)(nil)
+
+func NewRevocationStore() *RevocationStore {
+	return &RevocationStore{
+		tokens:  make(map[string]time.Time),
+		cutoffs: make(map[uint]time.Time),
+	}
+}
+
+func (s *RevocationStore) RevokeToken(ctx context.Context, jti string, userID uint, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purgeExpired(time.Now())
+	s.tokens[jti] = expiresAt
+	return nil
+}
+
+func (s *RevocationStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, revoked := s.tokens[jti]
+	return revoked, nil
+}
+
+func (s *RevocationStore) RevokeUserTokens(ctx context.Context, userID uint, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Se conserva el corte más reciente, como en RevocationRepository
+	if before.After(s.cutoffs[userID]) {
+		s.cutoffs[userID] = before.UTC()
+	}
+	return nil
+}
+
+func (s *RevocationStore) UserTokensRevokedBefore(ctx context.Context, userID uint) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cutoffs[userID], nil
+}
+
+func (s *RevocationStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.purgeExpired(now), nil
+}
+
+// purgeExpired elimina los jti de tokens que ya expiraron por sí solos
+func (s *RevocationStore) purgeExpired(now time.Time) int64 {
+	var purged int64
+	for jti, expiresAt := range s.tokens {
+		if now.After(expiresAt) {
+			delete(s.tokens, jti)
+			purged++
+		}
+	}
+	return purged
+}
@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopost-api/repositories"
+)
+
+func TestRevocationDeleteExpired(t *testing.T) {
+	stores := []struct {
+		name  string
+		store func() repositories.RevocationStore
+	}{
+		{name: "store", store: func() repositories.RevocationStore { return NewRevocationStore() }},
+		{name: "cache", store: func() repositories.RevocationStore { return NewRevocationCache(NewRevocationStore(), time.Minute) }},
+	}
+
+	for _, tt := range stores {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := tt.store()
+			now := time.Now().UTC()
+
+			if err := store.RevokeToken(ctx, "vencido", 1, now.Add(time.Minute)); err != nil {
+				t.Fatalf("RevokeToken: %v", err)
+			}
+			if err := store.RevokeToken(ctx, "vigente", 1, now.Add(time.Hour)); err != nil {
+				t.Fatalf("RevokeToken: %v", err)
+			}
+
+			deleted, err := store.DeleteExpired(ctx, now.Add(2*time.Minute))
+			if err != nil {
+				t.Fatalf("DeleteExpired: %v", err)
+			}
+			if deleted != 1 {
+				t.Errorf("DeleteExpired() = %d, se esperaba 1", deleted)
+			}
+
+			if revoked, _ := store.IsTokenRevoked(ctx, "vigente"); !revoked {
+				t.Error("el token vigente dejó de estar revocado")
+			}
+			if revoked, _ := store.IsTokenRevoked(ctx, "vencido"); revoked {
+				t.Error("la revocación vencida sigue registrada")
+			}
+		})
+	}
+}
+
+func TestRevocationCacheDeleteExpiredDropsEntries(t *testing.T) {
+	ctx := context.Background()
+	cache := NewRevocationCache(NewRevocationStore(), time.Minute)
+	now := time.Now().UTC()
+
+	if err := cache.RevokeToken(ctx, "vencido", 1, now.Add(time.Minute)); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if err := cache.RevokeToken(ctx, "vigente", 1, now.Add(time.Hour)); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if _, err := cache.DeleteExpired(ctx, now.Add(2*time.Minute)); err != nil {
+		t.Fatalf("DeleteExpired: %v", err)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if _, ok := cache.tokens["vencido"]; ok {
+		t.Error("la caché conserva la revocación vencida")
+	}
+	if _, ok := cache.tokens["vigente"]; !ok {
+		t.Error("la caché descartó la revocación vigente")
+	}
+}
+
+// pausedStore detiene las lecturas después de consultar el store, para
+// revocar en medio de una lectura de la caché
+type pausedStore struct {
+	*RevocationStore
+	read   chan struct{}
+	resume chan struct{}
+}
+
+func (s *pausedStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	revoked, err := s.RevocationStore.IsTokenRevoked(ctx, jti)
+	s.read <- struct{}{}
+	<-s.resume
+	return revoked, err
+}
+
+func (s *pausedStore) UserTokensRevokedBefore(ctx context.Context, userID uint) (time.Time, error) {
+	before, err := s.RevocationStore.UserTokensRevokedBefore(ctx, userID)
+	s.read <- struct{}{}
+	<-s.resume
+	return before, err
+}
+
+func TestRevocationCacheKeepsConcurrentRevocation(t *testing.T) {
+	ctx := context.Background()
+	store := &pausedStore{RevocationStore: NewRevocationStore(), read: make(chan struct{}), resume: make(chan struct{})}
+	cache := NewRevocationCache(store, time.Minute)
+	cutoff := time.Now().UTC().Truncate(time.Millisecond)
+
+	// Las lecturas ven el store antes de la revocación y la caché se
+	// actualiza recién después de ella
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.IsTokenRevoked(ctx, "jti")
+		cache.UserTokensRevokedBefore(ctx, 1)
+	}()
+
+	<-store.read
+	if err := cache.RevokeToken(ctx, "jti", 1, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	store.resume <- struct{}{}
+
+	<-store.read
+	if err := cache.RevokeUserTokens(ctx, 1, cutoff); err != nil {
+		t.Fatalf("RevokeUserTokens: %v", err)
+	}
+	store.resume <- struct{}{}
+	<-done
+
+	// Ya en caché, no se vuelve a consultar el store
+	if revoked, err := cache.IsTokenRevoked(ctx, "jti"); err != nil || !revoked {
+		t.Errorf("IsTokenRevoked() = %t, %v; la lectura concurrente pisó la revocación", revoked, err)
+	}
+	if before, err := cache.UserTokensRevokedBefore(ctx, 1); err != nil || !before.Equal(cutoff) {
+		t.Errorf("UserTokensRevokedBefore() = %v, %v; se esperaba el corte %v", before, err, cutoff)
+	}
+}
+
+func TestRevokeUserTokensKeepsLatestCutoff(t *testing.T) {
+	stores := []struct {
+		name  string
+		store func() repositories.RevocationStore
+	}{
+		{name: "store", store: func() repositories.RevocationStore { return NewRevocationStore() }},
+		{name: "cache", store: func() repositories.RevocationStore { return NewRevocationCache(NewRevocationStore(), time.Minute) }},
+	}
+
+	for _, tt := range stores {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := tt.store()
+			newer := time.Now().UTC().Truncate(time.Millisecond)
+			older := newer.Add(-time.Second)
+
+			// Dos logout-all concurrentes que se confirman en orden inverso
+			if err := store.RevokeUserTokens(ctx, 1, newer); err != nil {
+				t.Fatalf("RevokeUserTokens: %v", err)
+			}
+			if err := store.RevokeUserTokens(ctx, 1, older); err != nil {
+				t.Fatalf("RevokeUserTokens: %v", err)
+			}
+
+			if before, err := store.UserTokensRevokedBefore(ctx, 1); err != nil || !before.Equal(newer) {
+				t.Errorf("UserTokensRevokedBefore() = %v, %v; se esperaba el corte más reciente %v", before, err, newer)
+			}
+		})
+	}
+}
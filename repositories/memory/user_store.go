@@ -0,0 +1,237 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/repositories"
+)
+
+// UserStore guarda los usuarios en memoria con la misma semántica que
+// repositories.UserRepository, incluido el email único. Es seguro para
+// uso concurrente.
+type UserStore struct {
+	mu      sync.RWMutex
+	users   map[uint]models.User
+	byEmail map[string]uint
+	nextID  uint
+}
+
+var _ repositories.UserStore = (*UserStore)(nil)
+
+func NewUserStore() *UserStore {
+	return &UserStore{
+		users:   make(map[uint]models.User),
+		byEmail: make(map[string]uint),
+		nextID:  1,
+	}
+}
+
+func (s *UserStore) Create(ctx context.Context, user *models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.byEmail[emailKey(user.Email)]; exists {
+		return apperrors.Conflict("el email ya está registrado")
+	}
+
+	if user.Role == "" {
+		user.Role = models.RoleUser
+	}
+
+	now := database.Now()
+	stored := *user
+	stored.ID = s.nextID
+	stored.CreatedAt = now
+	stored.UpdatedAt = now
+	s.users[stored.ID] = stored
+	s.byEmail[emailKey(stored.Email)] = stored.ID
+	s.nextID++
+
+	user.ID = stored.ID
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	return nil
+}
+
+func (s *UserStore) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.byEmail[emailKey(email)]
+	if !ok {
+		return nil, apperrors.NotFound("usuario no encontrado")
+	}
+	user := s.users[id]
+	return &user, nil
+}
+
+// FindByID no devuelve la contraseña, igual que la consulta SQL
+func (s *UserStore) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return nil, apperrors.NotFound("usuario no encontrado")
+	}
+	user.Password = ""
+	return &user, nil
+}
+
+func (s *UserStore) EmailExists(ctx context.Context, email string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, exists := s.byEmail[emailKey(email)]
+	return exists, nil
+}
+
+func (s *UserStore) UpdateLastLogin(ctx context.Context, id uint, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return apperrors.NotFound("usuario no encontrado")
+	}
+	at = at.UTC().Truncate(time.Second)
+	user.LastLoginAt = &at
+	s.users[id] = user
+	return nil
+}
+
+func (s *UserStore) UpdateRole(ctx context.Context, id uint, role models.Role) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return apperrors.NotFound("usuario no encontrado")
+	}
+	user.Role = role
+	user.UpdatedAt = database.Now()
+	s.users[id] = user
+	return nil
+}
+
+func (s *UserStore) MarkEmailVerified(ctx context.Context, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return apperrors.NotFound("usuario no encontrado")
+	}
+	user.EmailVerified = true
+	user.UpdatedAt = database.Now()
+	s.users[id] = user
+	return nil
+}
+
+func (s *UserStore) UpdatePassword(ctx context.Context, id uint, passwordHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return apperrors.NotFound("usuario no encontrado")
+	}
+	user.Password = passwordHash
+	user.UpdatedAt = database.Now()
+	s.users[id] = user
+	return nil
+}
+
+func (s *UserStore) UpdateProfile(ctx context.Context, user *models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.users[user.ID]
+	if !ok {
+		return apperrors.NotFound("usuario no encontrado")
+	}
+	stored.Name = user.Name
+	stored.Bio = user.Bio
+	stored.AvatarURL = user.AvatarURL
+	stored.UpdatedAt = database.Now()
+	s.users[user.ID] = stored
+
+	user.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+func (s *UserStore) UpdateEmail(ctx context.Context, id uint, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return apperrors.NotFound("usuario no encontrado")
+	}
+	if owner, exists := s.byEmail[emailKey(email)]; exists && owner != id {
+		return apperrors.Conflict("el email ya está registrado")
+	}
+	delete(s.byEmail, emailKey(user.Email))
+	user.Email = email
+	user.EmailVerified = true
+	user.UpdatedAt = database.Now()
+	s.users[id] = user
+	s.byEmail[emailKey(email)] = id
+	return nil
+}
+
+func (s *UserStore) ScheduleDeletion(ctx context.Context, id uint, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return apperrors.NotFound("usuario no encontrado")
+	}
+	at = at.UTC().Truncate(time.Second)
+	user.DeletionRequestedAt = &at
+	s.users[id] = user
+	return nil
+}
+
+func (s *UserStore) CancelDeletion(ctx context.Context, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user, ok := s.users[id]; ok {
+		user.DeletionRequestedAt = nil
+		s.users[id] = user
+	}
+	return nil
+}
+
+func (s *UserStore) PendingDeletions(ctx context.Context, before time.Time) ([]uint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := []uint{}
+	for id, user := range s.users {
+		if pendingDeletion(user, before) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// pendingDeletion indica si el usuario pidió borrar su cuenta hasta before
+func pendingDeletion(user models.User, before time.Time) bool {
+	return user.DeletionRequestedAt != nil && !user.DeletionRequestedAt.After(before)
+}
+
+// emailKey normaliza el email como lo compara la collation *_ci de MySQL
+func emailKey(email string) string {
+	return strings.ToLower(email)
+}
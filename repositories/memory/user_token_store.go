@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/repositories"
+)
+
+// UserTokenStore guarda los tokens de un solo uso en memoria con la misma
+// semántica que repositories.UserTokenRepository. Es seguro para uso
+// concurrente.
+type UserTokenStore struct {
+	mu     sync.Mutex
+	tokens map[uint]models.UserToken
+	byHash map[string]uint
+	nextID uint
+}
+
+var _ repositories.UserTokenStore = (*UserTokenStore)(nil)
+
+func NewUserTokenStore() *UserTokenStore {
+	return &UserTokenStore{
+		tokens: make(map[uint]models.UserToken),
+		byHash: make(map[string]uint),
+		nextID: 1,
+	}
+}
+
+func (s *UserTokenStore) Create(ctx context.Context, token *models.UserToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.byHash[token.TokenHash]; exists {
+		return apperrors.Conflict("token duplicado")
+	}
+
+	stored := *token
+	stored.ID = s.nextID
+	stored.CreatedAt = database.Now()
+	s.tokens[stored.ID] = stored
+	s.byHash[stored.TokenHash] = stored.ID
+	s.nextID++
+
+	token.ID = stored.ID
+	token.CreatedAt = stored.CreatedAt
+	return nil
+}
+
+func (s *UserTokenStore) FindByHash(ctx context.Context, hash string) (*models.UserToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.byHash[hash]
+	if !ok {
+		return nil, apperrors.NotFound("token no encontrado")
+	}
+	token := s.tokens[id]
+	return &token, nil
+}
+
+func (s *UserTokenStore) MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[id]
+	if !ok || token.UsedAt != nil {
+		return false, nil
+	}
+	at = at.UTC()
+	token.UsedAt = &at
+	s.tokens[id] = token
+	return true, nil
+}
+
+func (s *UserTokenStore) InvalidateUser(ctx context.Context, userID uint, purpose string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at = at.UTC()
+	for id, token := range s.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			token.UsedAt = &at
+			s.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+func (s *UserTokenStore) LastCreatedAt(ctx context.Context, userID uint, purpose string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var last time.Time
+	for _, token := range s.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.CreatedAt.After(last) {
+			last = token.CreatedAt
+		}
+	}
+	return last, nil
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gopost-api/models"
+)
+
+// Límites de paginación compartidos por todas las implementaciones
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+// postSortColumns son los campos por los que se puede ordenar un listado
+var postSortColumns = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"title":      "title",
+	"id":         "id",
+}
+
+// PostSort indica el campo de ordenamiento y la dirección
+type PostSort struct {
+	Field string
+	Desc  bool
+}
+
+// DefaultPostSort ordena del post más reciente al más antiguo
+var DefaultPostSort = PostSort{Field: "created_at", Desc: true}
+
+// ParsePostSort interpreta valores como "created_at", "-updated_at" o "title".
+// El prefijo "-" indica orden descendente.
+func ParsePostSort(value string) (PostSort, error) {
+	if value == "" {
+		return DefaultPostSort, nil
+	}
+
+	sort := PostSort{Field: value}
+	if strings.HasPrefix(value, "-") {
+		sort = PostSort{Field: value[1:], Desc: true}
+	}
+
+	if _, ok := postSortColumns[sort.Field]; !ok {
+		return PostSort{}, fmt.Errorf("no se puede ordenar por %q", sort.Field)
+	}
+	return sort, nil
+}
+
+// String devuelve el ordenamiento en el mismo formato que acepta ParsePostSort
+func (s PostSort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// PostQuery describe un listado de posts acotado: filtros, orden y página
+type PostQuery struct {
+	UserID        uint
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Sort          PostSort
+	Limit         int
+	Offset        int
+}
+
+// Normalize completa los valores por defecto y acota el tamaño de página
+func (q PostQuery) Normalize() PostQuery {
+	if q.Sort.Field == "" {
+		q.Sort = DefaultPostSort
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultPerPage
+	}
+	if q.Limit > MaxPerPage {
+		q.Limit = MaxPerPage
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	return q
+}
+
+// PostKey identifica la posición de un post en el feed ordenado por
+// created_at DESC, id DESC. Es lo que codifica un cursor.
+type PostKey struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+// PostFeedQuery describe una página del feed por cursor (keyset): los posts
+// estrictamente posteriores a After en el orden created_at DESC, id DESC
+type PostFeedQuery struct {
+	UserID uint
+	After  *PostKey
+	Limit  int
+}
+
+// PostSearchQuery describe una página de resultados de búsqueda
+type PostSearchQuery struct {
+	Text   string
+	Limit  int
+	Offset int
+}
+
+// PostSearchHit es un post encontrado junto con su relevancia. La escala
+// del puntaje depende del motor; solo sirve para comparar resultados.
+type PostSearchHit struct {
+	Post  models.Post
+	Score float64
+}
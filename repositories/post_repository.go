@@ -4,92 +4,174 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/database"
 	"github.com/gopost-api/models"
 )
 
 type PostRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	dialect database.Dialect
 }
 
-func NewPostRepository(db *sql.DB) *PostRepository {
-	return &PostRepository{db: db}
+func NewPostRepository(db *sql.DB, dialect database.Dialect) *PostRepository {
+	return &PostRepository{db: db, dialect: dialect}
 }
 
 func (r *PostRepository) Create(ctx context.Context, post *models.Post) error {
-	query := "INSERT INTO posts (user_id, title, content) VALUES (?, ?, ?)"
-	result, err := r.db.ExecContext(ctx, query, post.UserID, post.Title, post.Content)
+	// Las fechas se fijan desde Go para devolverlas sin volver a consultar
+	now := database.Now()
+	query := "INSERT INTO posts (user_id, title, content, created_at, updated_at) VALUES (?, ?, ?, ?, ?)"
+	id, err := r.dialect.InsertID(ctx, r.db, query, post.UserID, post.Title, post.Content, r.dialect.TimeValue(now), r.dialect.TimeValue(now))
 	if err != nil {
 		return fmt.Errorf("error al crear post: %w", err)
 	}
 
-	id, err := result.LastInsertId()
+	post.ID = uint(id)
+	post.CreatedAt = now
+	post.UpdatedAt = now
+	return nil
+}
+
+// List devuelve una página de posts según los filtros y el orden de la
+// consulta, junto con el total de posts que cumplen los filtros
+func (r *PostRepository) List(ctx context.Context, q PostQuery) ([]models.Post, int, error) {
+	q = q.Normalize()
+	where, args := r.postFilters(q)
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM posts" + where
+	if err := r.db.QueryRowContext(ctx, r.dialect.Rebind(countQuery), args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error al contar posts: %w", err)
+	}
+
+	direction := "ASC"
+	if q.Sort.Desc {
+		direction = "DESC"
+	}
+	// El id desempata filas con el mismo valor para que el orden sea estable
+	query := fmt.Sprintf(
+		"SELECT id, user_id, title, content, created_at, updated_at FROM posts%s ORDER BY %s %s, id %s LIMIT ? OFFSET ?",
+		where, postSortColumns[q.Sort.Field], direction, direction,
+	)
+	args = append(args, q.Limit, q.Offset)
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
 	if err != nil {
-		return fmt.Errorf("error al obtener ID: %w", err)
+		return nil, 0, fmt.Errorf("error al obtener posts: %w", err)
 	}
+	defer rows.Close()
 
-	post.ID = uint(id)
-	return nil
+	posts, err := scanPosts(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return posts, total, nil
 }
 
-func (r *PostRepository) FindAll(ctx context.Context) ([]models.Post, error) {
-	query := "SELECT id, user_id, title, content, created_at, updated_at FROM posts ORDER BY created_at DESC"
-	rows, err := r.db.QueryContext(ctx, query)
+// Feed devuelve hasta q.Limit posts a partir de la posición q.After usando
+// una condición keyset, por lo que el costo no crece con la profundidad
+// y los posts nuevos no desplazan las páginas siguientes
+func (r *PostRepository) Feed(ctx context.Context, q PostFeedQuery) ([]models.Post, error) {
+	var conditions []string
+	var args []interface{}
+
+	if q.UserID != 0 {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, q.UserID)
+	}
+	if q.After != nil {
+		createdAt := r.dialect.TimeValue(q.After.CreatedAt)
+		conditions = append(conditions, "(created_at < ? OR (created_at = ? AND id < ?))")
+		args = append(args, createdAt, createdAt, q.After.ID)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := "SELECT id, user_id, title, content, created_at, updated_at FROM posts" + where + " ORDER BY created_at DESC, id DESC LIMIT ?"
+	args = append(args, q.Limit)
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
 	if err != nil {
-		return nil, fmt.Errorf("error al obtener posts: %w", err)
+		return nil, fmt.Errorf("error al obtener el feed de posts: %w", err)
 	}
 	defer rows.Close()
 
-	var posts []models.Post
+	return scanPosts(rows)
+}
+
+// postFilters arma la cláusula WHERE con los filtros de la consulta
+func (r *PostRepository) postFilters(q PostQuery) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if q.UserID != 0 {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, q.UserID)
+	}
+	if q.CreatedAfter != nil {
+		conditions = append(conditions, "created_at > ?")
+		args = append(args, r.dialect.TimeValue(*q.CreatedAfter))
+	}
+	if q.CreatedBefore != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, r.dialect.TimeValue(*q.CreatedBefore))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func scanPosts(rows *sql.Rows) ([]models.Post, error) {
+	posts := []models.Post{}
 	for rows.Next() {
 		var post models.Post
 		if err := rows.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.CreatedAt, &post.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("error al escanear post: %w", err)
 		}
-		posts = append(posts, post)
+		posts = append(posts, normalizePost(post))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al leer posts: %w", err)
 	}
-
 	return posts, nil
 }
 
 func (r *PostRepository) FindByID(ctx context.Context, id uint) (*models.Post, error) {
 	post := &models.Post{}
 	query := "SELECT id, user_id, title, content, created_at, updated_at FROM posts WHERE id = ?"
-	
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.CreatedAt, &post.UpdatedAt)
+
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), id).Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.CreatedAt, &post.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("post no encontrado")
+			return nil, apperrors.NotFound("post no encontrado")
 		}
 		return nil, fmt.Errorf("error al buscar post: %w", err)
 	}
 
+	*post = normalizePost(*post)
 	return post, nil
 }
 
-func (r *PostRepository) FindByUserID(ctx context.Context, userID uint) ([]models.Post, error) {
-	query := "SELECT id, user_id, title, content, created_at, updated_at FROM posts WHERE user_id = ? ORDER BY created_at DESC"
-	rows, err := r.db.QueryContext(ctx, query, userID)
-	if err != nil {
-		return nil, fmt.Errorf("error al obtener posts del usuario: %w", err)
-	}
-	defer rows.Close()
-
-	var posts []models.Post
-	for rows.Next() {
-		var post models.Post
-		if err := rows.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.CreatedAt, &post.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("error al escanear post: %w", err)
-		}
-		posts = append(posts, post)
-	}
-
-	return posts, nil
+// normalizePost expresa las fechas en UTC sin importar la zona horaria
+// con que las entregue el driver
+func normalizePost(post models.Post) models.Post {
+	post.CreatedAt = post.CreatedAt.UTC()
+	post.UpdatedAt = post.UpdatedAt.UTC()
+	return post
 }
 
 func (r *PostRepository) Update(ctx context.Context, post *models.Post) error {
-	query := "UPDATE posts SET title = ?, content = ? WHERE id = ?"
-	result, err := r.db.ExecContext(ctx, query, post.Title, post.Content, post.ID)
+	now := database.Now()
+	query := "UPDATE posts SET title = ?, content = ?, updated_at = ? WHERE id = ?"
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), post.Title, post.Content, r.dialect.TimeValue(now), post.ID)
 	if err != nil {
 		return fmt.Errorf("error al actualizar post: %w", err)
 	}
@@ -100,15 +182,16 @@ func (r *PostRepository) Update(ctx context.Context, post *models.Post) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("post no encontrado")
+		return apperrors.NotFound("post no encontrado")
 	}
 
+	post.UpdatedAt = now
 	return nil
 }
 
 func (r *PostRepository) Delete(ctx context.Context, id uint) error {
 	query := "DELETE FROM posts WHERE id = ?"
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), id)
 	if err != nil {
 		return fmt.Errorf("error al eliminar post: %w", err)
 	}
@@ -119,7 +202,7 @@ func (r *PostRepository) Delete(ctx context.Context, id uint) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("post no encontrado")
+		return apperrors.NotFound("post no encontrado")
 	}
 
 	return nil
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/gopost-api/database"
+)
+
+// SearchTerms separa el texto de búsqueda en palabras, como lo hace la
+// búsqueda por LIKE de SQLite y la implementación en memoria
+func SearchTerms(text string) []string {
+	return strings.Fields(strings.ToLower(text))
+}
+
+// Search busca posts por título y contenido ordenados por relevancia.
+// MySQL usa el índice FULLTEXT, PostgreSQL su búsqueda de texto completo y
+// SQLite una búsqueda por LIKE en la que deben aparecer todas las palabras.
+func (r *PostRepository) Search(ctx context.Context, q PostSearchQuery) ([]PostSearchHit, int, error) {
+	var where, score string
+	var whereArgs, scoreArgs []interface{}
+
+	switch r.dialect.Name() {
+	case database.DriverMySQL:
+		match := "MATCH(title, content) AGAINST (? IN NATURAL LANGUAGE MODE)"
+		where, score = match, match
+		whereArgs, scoreArgs = []interface{}{q.Text}, []interface{}{q.Text}
+
+	case database.DriverPostgres:
+		document := "to_tsvector('simple', title || ' ' || content)"
+		where = document + " @@ plainto_tsquery('simple', ?)"
+		score = "ts_rank(" + document + ", plainto_tsquery('simple', ?))"
+		whereArgs, scoreArgs = []interface{}{q.Text}, []interface{}{q.Text}
+
+	default:
+		where, whereArgs, score, scoreArgs = likeSearch(SearchTerms(q.Text))
+	}
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM posts WHERE " + where
+	if err := r.db.QueryRowContext(ctx, r.dialect.Rebind(countQuery), whereArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error al contar resultados: %w", err)
+	}
+
+	query := "SELECT id, user_id, title, content, created_at, updated_at, " + score + " AS score FROM posts WHERE " + where +
+		" ORDER BY score DESC, created_at DESC, id DESC LIMIT ? OFFSET ?"
+	args := append(append(scoreArgs, whereArgs...), q.Limit, q.Offset)
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error al buscar posts: %w", err)
+	}
+	defer rows.Close()
+
+	hits, err := scanSearchHits(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hits, total, nil
+}
+
+// likeSearch arma la búsqueda por LIKE: cada palabra debe aparecer en el
+// título o en el contenido, y puntúa doble las coincidencias en el título.
+// Usa unicode_lower, que el driver de SQLite registra porque su LOWER solo
+// pasa a minúsculas las letras ASCII.
+func likeSearch(terms []string) (where string, whereArgs []interface{}, score string, scoreArgs []interface{}) {
+	var conditions, points []string
+	for _, term := range terms {
+		pattern := "%" + escapeLike(term) + "%"
+		conditions = append(conditions, `(unicode_lower(title) LIKE ? ESCAPE '\' OR unicode_lower(content) LIKE ? ESCAPE '\')`)
+		whereArgs = append(whereArgs, pattern, pattern)
+		points = append(points, `(CASE WHEN unicode_lower(title) LIKE ? ESCAPE '\' THEN 2 ELSE 0 END + CASE WHEN unicode_lower(content) LIKE ? ESCAPE '\' THEN 1 ELSE 0 END)`)
+		scoreArgs = append(scoreArgs, pattern, pattern)
+	}
+	return strings.Join(conditions, " AND "), whereArgs, "(" + strings.Join(points, " + ") + ")", scoreArgs
+}
+
+// escapeLike escapa los comodines de LIKE para buscar el texto literal
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
+
+func scanSearchHits(rows *sql.Rows) ([]PostSearchHit, error) {
+	hits := []PostSearchHit{}
+	for rows.Next() {
+		var hit PostSearchHit
+		post := &hit.Post
+		if err := rows.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.CreatedAt, &post.UpdatedAt, &hit.Score); err != nil {
+			return nil, fmt.Errorf("error al escanear resultado: %w", err)
+		}
+		hit.Post = normalizePost(hit.Post)
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al leer resultados: %w", err)
+	}
+	return hits, nil
+}
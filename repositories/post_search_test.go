@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopost-api/models"
+)
+
+// En SQLite la búsqueda por LIKE pasa a minúsculas con unicode_lower: el
+// LOWER de SQLite solo convierte letras ASCII
+func TestPostRepositorySearchSQLite(t *testing.T) {
+	ctx := context.Background()
+	db, dialect := openTestDB(t)
+	repo := NewPostRepository(db, dialect)
+
+	if _, err := db.Exec("INSERT INTO users (name, email, password) VALUES ('Ana', 'ana@example.com', 'x')"); err != nil {
+		t.Fatalf("crear usuario: %v", err)
+	}
+	var ids []uint
+	for _, p := range []struct{ title, content string }{
+		{"Viaje con Álvaro", "Un recorrido por el sur"},
+		{"Recetas", "La receta favorita de ÁLVARO"},
+		{"Descuentos", "Todo al 50% y envío_gratis"},
+	} {
+		post := &models.Post{UserID: 1, Title: p.title, Content: p.content}
+		if err := repo.Create(ctx, post); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		ids = append(ids, post.ID)
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		wantIDs []uint
+	}{
+		{name: "palabra no ASCII en minúsculas", text: "álvaro", wantIDs: []uint{ids[0], ids[1]}},
+		{name: "palabra no ASCII en mayúsculas", text: "ÁLVARO", wantIDs: []uint{ids[0], ids[1]}},
+		{name: "todas las palabras", text: "Álvaro SUR", wantIDs: []uint{ids[0]}},
+		{name: "comodín % literal", text: "50%", wantIDs: []uint{ids[2]}},
+		{name: "comodín _ literal", text: "envío_gratis", wantIDs: []uint{ids[2]}},
+		{name: "el comodín no coincide con otro carácter", text: "env%gratis"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, total, err := repo.Search(ctx, PostSearchQuery{Text: tt.text, Limit: 10})
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if total != len(tt.wantIDs) || len(hits) != len(tt.wantIDs) {
+				t.Fatalf("Search() = %d resultados (total %d), se esperaban %d", len(hits), total, len(tt.wantIDs))
+			}
+			// La coincidencia en el título vale doble y va primero
+			for i, hit := range hits {
+				if hit.Post.ID != tt.wantIDs[i] {
+					t.Errorf("resultado %d = post %d, se esperaba %d", i, hit.Post.ID, tt.wantIDs[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/models"
+)
+
+type RefreshTokenRepository struct {
+	db      *sql.DB
+	dialect database.Dialect
+}
+
+func NewRefreshTokenRepository(db *sql.DB, dialect database.Dialect) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db, dialect: dialect}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	now := database.Now()
+	query := "INSERT INTO refresh_tokens (user_id, family_id, token_hash, created_at, expires_at) VALUES (?, ?, ?, ?, ?)"
+	id, err := r.dialect.InsertID(ctx, r.db, query,
+		token.UserID, token.FamilyID, token.TokenHash, r.dialect.TimeValue(now), r.dialect.TimeValue(token.ExpiresAt),
+	)
+	if err != nil {
+		return fmt.Errorf("error al crear refresh token: %w", err)
+	}
+
+	token.ID = uint(id)
+	token.CreatedAt = now
+	return nil
+}
+
+func (r *RefreshTokenRepository) FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	token := &models.RefreshToken{}
+	var usedAt, revokedAt sql.NullTime
+	query := "SELECT id, user_id, family_id, token_hash, created_at, expires_at, used_at, revoked_at FROM refresh_tokens WHERE token_hash = ?"
+
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), hash).Scan(
+		&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash, &token.CreatedAt, &token.ExpiresAt, &usedAt, &revokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("refresh token no encontrado")
+		}
+		return nil, fmt.Errorf("error al buscar refresh token: %w", err)
+	}
+
+	token.CreatedAt = token.CreatedAt.UTC()
+	token.ExpiresAt = token.ExpiresAt.UTC()
+	token.UsedAt = nullTimePtr(usedAt)
+	token.RevokedAt = nullTimePtr(revokedAt)
+	return token, nil
+}
+
+// MarkUsed hace la rotación atómica: la condición sobre used_at y
+// revoked_at garantiza que solo un pedido concurrente pueda usar el token
+func (r *RefreshTokenRepository) MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error) {
+	query := "UPDATE refresh_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL AND revoked_at IS NULL"
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), r.dialect.TimeValue(at), id)
+	if err != nil {
+		return false, fmt.Errorf("error al usar refresh token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error al verificar actualización: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// RevokeFamily revoca todos los tokens de la familia que sigan activos
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	query := "UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL"
+	if _, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), r.dialect.TimeValue(at), familyID); err != nil {
+		return fmt.Errorf("error al revocar refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// RevokeUser revoca todos los tokens activos del usuario
+func (r *RefreshTokenRepository) RevokeUser(ctx context.Context, userID uint, at time.Time) error {
+	query := "UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL"
+	if _, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), r.dialect.TimeValue(at), userID); err != nil {
+		return fmt.Errorf("error al revocar refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// nullTimePtr convierte una fecha opcional en un puntero en UTC
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	utc := t.Time.UTC()
+	return &utc
+}
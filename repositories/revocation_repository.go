@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gopost-api/database"
+)
+
+type RevocationRepository struct {
+	db      *sql.DB
+	dialect database.Dialect
+}
+
+func NewRevocationRepository(db *sql.DB, dialect database.Dialect) *RevocationRepository {
+	return &RevocationRepository{db: db, dialect: dialect}
+}
+
+func (r *RevocationRepository) RevokeToken(ctx context.Context, jti string, userID uint, expiresAt time.Time) error {
+	query := "INSERT INTO revoked_tokens (jti, user_id, revoked_at, expires_at) VALUES (?, ?, ?, ?)"
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(query),
+		jti, userID, r.dialect.TimeValue(database.Now()), r.dialect.TimeValue(expiresAt),
+	)
+	// Revocar dos veces el mismo token no es un error
+	if err != nil && !isUniqueViolation(err) {
+		return fmt.Errorf("error al revocar token: %w", err)
+	}
+	return nil
+}
+
+func (r *RevocationRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM revoked_tokens WHERE jti = ?"
+
+	if err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), jti).Scan(&count); err != nil {
+		return false, fmt.Errorf("error al verificar token: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *RevocationRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	query := "DELETE FROM revoked_tokens WHERE expires_at < ?"
+
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), r.dialect.TimeValue(now))
+	if err != nil {
+		return 0, fmt.Errorf("error al borrar revocaciones expiradas: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error al borrar revocaciones expiradas: %w", err)
+	}
+	return deleted, nil
+}
+
+// RevokeUserTokens guarda un único corte por usuario y conserva siempre el
+// más reciente: dos llamadas concurrentes pueden confirmarse en cualquier
+// orden y un corte anterior no debe volver a habilitar tokens ya revocados.
+// El corte conserva los milisegundos, así que se guarda tal cual en lugar
+// de pasar por TimeValue; en SQLite el texto de ese formato se ordena igual
+// que las fechas.
+func (r *RevocationRepository) RevokeUserTokens(ctx context.Context, userID uint, before time.Time) error {
+	var query string
+	switch r.dialect.Name() {
+	case database.DriverMySQL:
+		query = "INSERT INTO user_token_revocations (user_id, revoked_before) VALUES (?, ?) ON DUPLICATE KEY UPDATE revoked_before = GREATEST(revoked_before, VALUES(revoked_before))"
+	default:
+		query = "INSERT INTO user_token_revocations (user_id, revoked_before) VALUES (?, ?) ON CONFLICT (user_id) DO UPDATE SET revoked_before = excluded.revoked_before WHERE excluded.revoked_before > user_token_revocations.revoked_before"
+	}
+
+	if _, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), userID, before.UTC()); err != nil {
+		return fmt.Errorf("error al revocar los tokens del usuario: %w", err)
+	}
+	return nil
+}
+
+func (r *RevocationRepository) UserTokensRevokedBefore(ctx context.Context, userID uint) (time.Time, error) {
+	var before time.Time
+	query := "SELECT revoked_before FROM user_token_revocations WHERE user_id = ?"
+
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), userID).Scan(&before)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("error al verificar tokens del usuario: %w", err)
+	}
+	return before.UTC(), nil
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/database/migrations"
+)
+
+// openTestDB abre una base SQLite en memoria con todas las migraciones
+func openTestDB(t *testing.T) (*sql.DB, database.Dialect) {
+	t.Helper()
+	driver, dsn, err := database.ParseURL(":memory:")
+	if err != nil {
+		t.Fatalf("ParseURL: %v", err)
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	// Cada conexión a :memory: es una base distinta
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.New(db, driver)
+	if err != nil {
+		t.Fatalf("migrations.New: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	return db, database.DialectFor(driver)
+}
+
+func TestRevocationRepositoryDeleteExpired(t *testing.T) {
+	ctx := context.Background()
+	db, dialect := openTestDB(t)
+	repo := NewRevocationRepository(db, dialect)
+
+	if _, err := db.Exec("INSERT INTO users (name, email, password) VALUES ('Ana', 'ana@example.com', 'x')"); err != nil {
+		t.Fatalf("crear usuario: %v", err)
+	}
+
+	now := database.Now()
+	if err := repo.RevokeToken(ctx, "vencido", 1, now.Add(-time.Minute)); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if err := repo.RevokeToken(ctx, "vigente", 1, now.Add(time.Hour)); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+
+	deleted, err := repo.DeleteExpired(ctx, now)
+	if err != nil {
+		t.Fatalf("DeleteExpired: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("DeleteExpired() = %d, se esperaba 1", deleted)
+	}
+
+	for jti, want := range map[string]bool{"vencido": false, "vigente": true} {
+		revoked, err := repo.IsTokenRevoked(ctx, jti)
+		if err != nil {
+			t.Fatalf("IsTokenRevoked: %v", err)
+		}
+		if revoked != want {
+			t.Errorf("IsTokenRevoked(%q) = %t, se esperaba %t", jti, revoked, want)
+		}
+	}
+}
+
+func TestRevocationRepositoryKeepsLatestCutoff(t *testing.T) {
+	ctx := context.Background()
+	db, dialect := openTestDB(t)
+	repo := NewRevocationRepository(db, dialect)
+
+	base := time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		writes []time.Time
+		want   time.Time
+	}{
+		{name: "un corte posterior reemplaza al anterior", writes: []time.Time{base, base.Add(5 * time.Millisecond)}, want: base.Add(5 * time.Millisecond)},
+		{name: "un corte anterior no pisa al posterior", writes: []time.Time{base.Add(500 * time.Millisecond), base.Add(120 * time.Millisecond)}, want: base.Add(500 * time.Millisecond)},
+		{name: "con y sin milisegundos", writes: []time.Time{base.Add(time.Second), base.Add(999 * time.Millisecond)}, want: base.Add(time.Second)},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uint(i + 1)
+			for _, before := range tt.writes {
+				if err := repo.RevokeUserTokens(ctx, userID, before); err != nil {
+					t.Fatalf("RevokeUserTokens: %v", err)
+				}
+			}
+
+			before, err := repo.UserTokensRevokedBefore(ctx, userID)
+			if err != nil {
+				t.Fatalf("UserTokensRevokedBefore: %v", err)
+			}
+			if !before.Equal(tt.want) {
+				t.Errorf("UserTokensRevokedBefore() = %v, se esperaba %v", before, tt.want)
+			}
+		})
+	}
+}
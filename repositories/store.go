@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/gopost-api/models"
+)
+
+// PostStore define el acceso a los posts que necesitan los servicios.
+// Lo implementan PostRepository (SQL) y memory.PostStore (en memoria).
+type PostStore interface {
+	Create(ctx context.Context, post *models.Post) error
+	List(ctx context.Context, q PostQuery) ([]models.Post, int, error)
+	Feed(ctx context.Context, q PostFeedQuery) ([]models.Post, error)
+	Search(ctx context.Context, q PostSearchQuery) ([]PostSearchHit, int, error)
+	FindByID(ctx context.Context, id uint) (*models.Post, error)
+	Update(ctx context.Context, post *models.Post) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// UserStore define el acceso a los usuarios que necesitan los servicios.
+// Lo implementan UserRepository (SQL) y memory.UserStore (en memoria).
+type UserStore interface {
+	Create(ctx context.Context, user *models.User) error
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+	EmailExists(ctx context.Context, email string) (bool, error)
+	UpdateLastLogin(ctx context.Context, id uint, at time.Time) error
+	UpdateRole(ctx context.Context, id uint, role models.Role) error
+	MarkEmailVerified(ctx context.Context, id uint) error
+	UpdatePassword(ctx context.Context, id uint, passwordHash string) error
+	UpdateProfile(ctx context.Context, user *models.User) error
+	UpdateEmail(ctx context.Context, id uint, email string) error
+	ScheduleDeletion(ctx context.Context, id uint, at time.Time) error
+	CancelDeletion(ctx context.Context, id uint) error
+	// PendingDeletions devuelve los usuarios que pidieron borrar su cuenta
+	// hasta el instante indicado
+	PendingDeletions(ctx context.Context, before time.Time) ([]uint, error)
+}
+
+// AccountStore borra cuentas junto con lo que depende de ellas. Lo
+// implementan AccountRepository (SQL) y memory.AccountStore (en memoria).
+type AccountStore interface {
+	// PurgeUser borra al usuario en una sola transacción si pidió la
+	// eliminación hasta before; devuelve false si ya no estaba pendiente
+	PurgeUser(ctx context.Context, id uint, before time.Time, posts PostsOnDelete) (bool, error)
+}
+
+// RefreshTokenStore define el acceso a los refresh tokens que necesita
+// TokenService. Lo implementan RefreshTokenRepository (SQL) y
+// memory.RefreshTokenStore (en memoria).
+type RefreshTokenStore interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	// MarkUsed marca el token como usado solo si seguía vigente; devuelve
+	// false si otro pedido ya lo había usado o revocado
+	MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string, at time.Time) error
+	RevokeUser(ctx context.Context, userID uint, at time.Time) error
+}
+
+// UserTokenStore define el acceso a los tokens de un solo uso que se
+// envían por email. Lo implementan UserTokenRepository (SQL) y
+// memory.UserTokenStore (en memoria).
+type UserTokenStore interface {
+	Create(ctx context.Context, token *models.UserToken) error
+	FindByHash(ctx context.Context, hash string) (*models.UserToken, error)
+	// MarkUsed marca el token como usado solo si no lo estaba; devuelve
+	// false si otro pedido ya lo había usado
+	MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error)
+	// InvalidateUser marca como usados los tokens pendientes del usuario
+	// con ese propósito, por ejemplo al enviar uno nuevo
+	InvalidateUser(ctx context.Context, userID uint, purpose string, at time.Time) error
+	// LastCreatedAt devuelve cuándo se emitió el último token del usuario
+	// con ese propósito, o el instante cero si no hay ninguno
+	LastCreatedAt(ctx context.Context, userID uint, purpose string) (time.Time, error)
+}
+
+// RevocationStore registra los access tokens revocados antes de expirar.
+// Lo implementan RevocationRepository (SQL), memory.RevocationStore (en
+// memoria) y memory.RevocationCache, que agrega una caché a otro store.
+type RevocationStore interface {
+	// RevokeToken revoca un token por su jti hasta su expiración
+	RevokeToken(ctx context.Context, jti string, userID uint, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	// RevokeUserTokens revoca todos los tokens del usuario emitidos en el
+	// instante indicado o antes. Un corte anterior al ya guardado no lo
+	// reemplaza.
+	RevokeUserTokens(ctx context.Context, userID uint, before time.Time) error
+	// UserTokensRevokedBefore devuelve el último corte registrado para el
+	// usuario, o el instante cero si nunca se revocaron sus tokens
+	UserTokensRevokedBefore(ctx context.Context, userID uint) (time.Time, error)
+	// DeleteExpired borra las revocaciones de tokens que expiraron antes
+	// de now, que ya no hace falta recordar, y devuelve cuántas borró
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+var (
+	_ PostStore         = (*PostRepository)(nil)
+	_ UserStore         = (*UserRepository)(nil)
+	_ RefreshTokenStore = (*RefreshTokenRepository)(nil)
+	_ RevocationStore   = (*RevocationRepository)(nil)
+	_ UserTokenStore    = (*UserTokenRepository)(nil)
+	_ AccountStore      = (*AccountRepository)(nil)
+)
@@ -4,72 +4,280 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/database"
 	"github.com/gopost-api/models"
 )
 
 type UserRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	dialect database.Dialect
 }
 
-func NewUserRepository(db *sql.DB) *UserRepository {
-	return &UserRepository{db: db}
+func NewUserRepository(db *sql.DB, dialect database.Dialect) *UserRepository {
+	return &UserRepository{db: db, dialect: dialect}
 }
 
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
-	query := "INSERT INTO users (name, email, password) VALUES (?, ?, ?)"
-	result, err := r.db.ExecContext(ctx, query, user.Name, user.Email, user.Password)
-	if err != nil {
-		return fmt.Errorf("error al crear usuario: %w", err)
+	now := database.Now()
+	if user.Role == "" {
+		user.Role = models.RoleUser
 	}
-
-	id, err := result.LastInsertId()
+	query := "INSERT INTO users (name, email, email_verified, password, role, bio, avatar_url, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	id, err := r.dialect.InsertID(ctx, r.db, query,
+		user.Name, user.Email, user.EmailVerified, user.Password, user.Role, user.Bio, user.AvatarURL, r.dialect.TimeValue(now), r.dialect.TimeValue(now),
+	)
 	if err != nil {
-		return fmt.Errorf("error al obtener ID: %w", err)
+		if isUniqueViolation(err) {
+			return apperrors.Conflict("el email ya está registrado")
+		}
+		return fmt.Errorf("error al crear usuario: %w", err)
 	}
 
 	user.ID = uint(id)
+	user.CreatedAt = now
+	user.UpdatedAt = now
 	return nil
 }
 
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	user := &models.User{}
-	query := "SELECT id, name, email, password FROM users WHERE email = ?"
-	
-	err := r.db.QueryRowContext(ctx, query, email).Scan(&user.ID, &user.Name, &user.Email, &user.Password)
+	var lastLogin sql.NullTime
+	var deletionRequested sql.NullTime
+	query := "SELECT id, name, email, email_verified, password, role, bio, avatar_url, created_at, updated_at, last_login_at, deletion_requested_at FROM users WHERE email = ?"
+
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), email).Scan(
+		&user.ID, &user.Name, &user.Email, &user.EmailVerified, &user.Password, &user.Role, &user.Bio, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt, &lastLogin, &deletionRequested,
+	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("usuario no encontrado")
+			return nil, apperrors.NotFound("usuario no encontrado")
 		}
 		return nil, fmt.Errorf("error al buscar usuario: %w", err)
 	}
 
+	normalizeUser(user, lastLogin, deletionRequested)
 	return user, nil
 }
 
 func (r *UserRepository) FindByID(ctx context.Context, id uint) (*models.User, error) {
 	user := &models.User{}
-	query := "SELECT id, name, email FROM users WHERE id = ?"
-	
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&user.ID, &user.Name, &user.Email)
+	var lastLogin sql.NullTime
+	var deletionRequested sql.NullTime
+	query := "SELECT id, name, email, email_verified, role, bio, avatar_url, created_at, updated_at, last_login_at, deletion_requested_at FROM users WHERE id = ?"
+
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), id).Scan(
+		&user.ID, &user.Name, &user.Email, &user.EmailVerified, &user.Role, &user.Bio, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt, &lastLogin, &deletionRequested,
+	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("usuario no encontrado")
+			return nil, apperrors.NotFound("usuario no encontrado")
 		}
 		return nil, fmt.Errorf("error al buscar usuario: %w", err)
 	}
 
+	normalizeUser(user, lastLogin, deletionRequested)
 	return user, nil
 }
 
 func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
 	var count int
 	query := "SELECT COUNT(*) FROM users WHERE email = ?"
-	
-	err := r.db.QueryRowContext(ctx, query, email).Scan(&count)
+
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), email).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("error al verificar email: %w", err)
 	}
 
 	return count > 0, nil
 }
+
+// UpdateLastLogin registra el instante del último inicio de sesión
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, id uint, at time.Time) error {
+	query := "UPDATE users SET last_login_at = ? WHERE id = ?"
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), r.dialect.TimeValue(at.Truncate(time.Second)), id)
+	if err != nil {
+		return fmt.Errorf("error al registrar el inicio de sesión: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar actualización: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound("usuario no encontrado")
+	}
+
+	return nil
+}
+
+// UpdateRole cambia el rol del usuario
+func (r *UserRepository) UpdateRole(ctx context.Context, id uint, role models.Role) error {
+	query := "UPDATE users SET role = ?, updated_at = ? WHERE id = ?"
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), role, r.dialect.TimeValue(database.Now()), id)
+	if err != nil {
+		return fmt.Errorf("error al actualizar el rol: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar actualización: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound("usuario no encontrado")
+	}
+
+	return nil
+}
+
+// MarkEmailVerified marca como verificado el email actual del usuario
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id uint) error {
+	query := "UPDATE users SET email_verified = ?, updated_at = ? WHERE id = ?"
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), true, r.dialect.TimeValue(database.Now()), id)
+	if err != nil {
+		return fmt.Errorf("error al verificar el email: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar actualización: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound("usuario no encontrado")
+	}
+
+	return nil
+}
+
+// UpdatePassword reemplaza el hash de la contraseña del usuario
+func (r *UserRepository) UpdatePassword(ctx context.Context, id uint, passwordHash string) error {
+	query := "UPDATE users SET password = ?, updated_at = ? WHERE id = ?"
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), passwordHash, r.dialect.TimeValue(database.Now()), id)
+	if err != nil {
+		return fmt.Errorf("error al actualizar la contraseña: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar actualización: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound("usuario no encontrado")
+	}
+
+	return nil
+}
+
+// UpdateProfile guarda el nombre y los datos de perfil del usuario
+func (r *UserRepository) UpdateProfile(ctx context.Context, user *models.User) error {
+	now := database.Now()
+	query := "UPDATE users SET name = ?, bio = ?, avatar_url = ?, updated_at = ? WHERE id = ?"
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), user.Name, user.Bio, user.AvatarURL, r.dialect.TimeValue(now), user.ID)
+	if err != nil {
+		return fmt.Errorf("error al actualizar el perfil: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar actualización: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound("usuario no encontrado")
+	}
+
+	user.UpdatedAt = now
+	return nil
+}
+
+// UpdateEmail cambia el email del usuario y lo deja verificado: solo se
+// llama después de confirmar la nueva dirección
+func (r *UserRepository) UpdateEmail(ctx context.Context, id uint, email string) error {
+	query := "UPDATE users SET email = ?, email_verified = ?, updated_at = ? WHERE id = ?"
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), email, true, r.dialect.TimeValue(database.Now()), id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return apperrors.Conflict("el email ya está registrado")
+		}
+		return fmt.Errorf("error al actualizar el email: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar actualización: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound("usuario no encontrado")
+	}
+
+	return nil
+}
+
+// ScheduleDeletion registra el pedido de borrar la cuenta; se purga cuando
+// vence el período de gracia
+func (r *UserRepository) ScheduleDeletion(ctx context.Context, id uint, at time.Time) error {
+	query := "UPDATE users SET deletion_requested_at = ? WHERE id = ?"
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), r.dialect.TimeValue(at.UTC().Truncate(time.Second)), id)
+	if err != nil {
+		return fmt.Errorf("error al programar la eliminación de la cuenta: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar actualización: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound("usuario no encontrado")
+	}
+
+	return nil
+}
+
+// CancelDeletion anula un pedido de eliminación pendiente
+func (r *UserRepository) CancelDeletion(ctx context.Context, id uint) error {
+	query := "UPDATE users SET deletion_requested_at = NULL WHERE id = ?"
+	if _, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), id); err != nil {
+		return fmt.Errorf("error al cancelar la eliminación de la cuenta: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepository) PendingDeletions(ctx context.Context, before time.Time) ([]uint, error) {
+	query := "SELECT id FROM users WHERE deletion_requested_at IS NOT NULL AND deletion_requested_at <= ? ORDER BY id"
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), r.dialect.TimeValue(before))
+	if err != nil {
+		return nil, fmt.Errorf("error al buscar cuentas a eliminar: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []uint{}
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error al escanear usuario: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al leer usuarios: %w", err)
+	}
+	return ids, nil
+}
+
+// normalizeUser expresa las fechas en UTC y completa las fechas opcionales:
+// last_login_at es NULL hasta el primer inicio de sesión y
+// deletion_requested_at mientras la cuenta no pida ser borrada
+func normalizeUser(user *models.User, lastLogin, deletionRequested sql.NullTime) {
+	user.CreatedAt = user.CreatedAt.UTC()
+	user.UpdatedAt = user.UpdatedAt.UTC()
+	user.LastLoginAt = nullTimePtr(lastLogin)
+	user.DeletionRequestedAt = nullTimePtr(deletionRequested)
+}
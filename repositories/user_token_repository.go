@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/models"
+)
+
+type UserTokenRepository struct {
+	db      *sql.DB
+	dialect database.Dialect
+}
+
+func NewUserTokenRepository(db *sql.DB, dialect database.Dialect) *UserTokenRepository {
+	return &UserTokenRepository{db: db, dialect: dialect}
+}
+
+func (r *UserTokenRepository) Create(ctx context.Context, token *models.UserToken) error {
+	now := database.Now()
+	query := "INSERT INTO user_tokens (user_id, purpose, token_hash, email, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?)"
+	id, err := r.dialect.InsertID(ctx, r.db, query,
+		token.UserID, token.Purpose, token.TokenHash, token.Email, r.dialect.TimeValue(now), r.dialect.TimeValue(token.ExpiresAt),
+	)
+	if err != nil {
+		return fmt.Errorf("error al crear token: %w", err)
+	}
+
+	token.ID = uint(id)
+	token.CreatedAt = now
+	return nil
+}
+
+func (r *UserTokenRepository) FindByHash(ctx context.Context, hash string) (*models.UserToken, error) {
+	token := &models.UserToken{}
+	var usedAt sql.NullTime
+	query := "SELECT id, user_id, purpose, token_hash, email, created_at, expires_at, used_at FROM user_tokens WHERE token_hash = ?"
+
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), hash).Scan(
+		&token.ID, &token.UserID, &token.Purpose, &token.TokenHash, &token.Email, &token.CreatedAt, &token.ExpiresAt, &usedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("token no encontrado")
+		}
+		return nil, fmt.Errorf("error al buscar token: %w", err)
+	}
+
+	token.CreatedAt = token.CreatedAt.UTC()
+	token.ExpiresAt = token.ExpiresAt.UTC()
+	token.UsedAt = nullTimePtr(usedAt)
+	return token, nil
+}
+
+func (r *UserTokenRepository) MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error) {
+	query := "UPDATE user_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL"
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), r.dialect.TimeValue(at), id)
+	if err != nil {
+		return false, fmt.Errorf("error al usar token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error al verificar actualización: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *UserTokenRepository) InvalidateUser(ctx context.Context, userID uint, purpose string, at time.Time) error {
+	query := "UPDATE user_tokens SET used_at = ? WHERE user_id = ? AND purpose = ? AND used_at IS NULL"
+	if _, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), r.dialect.TimeValue(at), userID, purpose); err != nil {
+		return fmt.Errorf("error al invalidar tokens: %w", err)
+	}
+	return nil
+}
+
+func (r *UserTokenRepository) LastCreatedAt(ctx context.Context, userID uint, purpose string) (time.Time, error) {
+	var createdAt time.Time
+	query := "SELECT created_at FROM user_tokens WHERE user_id = ? AND purpose = ? ORDER BY created_at DESC LIMIT 1"
+
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), userID, purpose).Scan(&createdAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("error al buscar tokens: %w", err)
+	}
+	return createdAt.UTC(), nil
+}
@@ -4,25 +4,39 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"sync/atomic"
 )
 
 type Context struct {
-	RWriter http.ResponseWriter
-	Request *http.Request
-	Ctx     context.Context
-	userID  uint
+	RWriter  http.ResponseWriter
+	Request  *http.Request
+	Ctx      context.Context
+	userID   uint
+	values   map[string]interface{}
+	timedOut atomic.Bool
+
+	errorHandler ErrorHandler
 }
 
 func (c *Context) Send(text string) {
+	if c.TimedOut() {
+		return
+	}
 	c.RWriter.Write([]byte(text))
 }
 
 func (c *Context) Status(code int) {
+	if c.TimedOut() {
+		return
+	}
 	c.RWriter.WriteHeader(code)
 }
 
 // JSON envía una respuesta en formato JSON
 func (c *Context) JSON(code int, data interface{}) error {
+	if c.TimedOut() {
+		return http.ErrHandlerTimeout
+	}
 	c.RWriter.Header().Set("Content-Type", "application/json")
 	c.RWriter.WriteHeader(code)
 	return json.NewEncoder(c.RWriter).Encode(data)
@@ -33,6 +47,18 @@ func (c *Context) BindJSON(v interface{}) error {
 	return json.NewDecoder(c.Request.Body).Decode(v)
 }
 
+// Error responde con el error usando el ErrorHandler de la App
+func (c *Context) Error(err error) {
+	if err == nil || c.TimedOut() {
+		return
+	}
+	if c.errorHandler == nil {
+		DefaultErrorHandler(c, err)
+		return
+	}
+	c.errorHandler(c, err)
+}
+
 // SetUserID establece el ID del usuario en el contexto
 func (c *Context) SetUserID(id uint) {
 	c.userID = id
@@ -43,7 +69,55 @@ func (c *Context) GetUserID() uint {
 	return c.userID
 }
 
+// Set guarda un valor asociado a la petición, por ejemplo datos que un
+// middleware deja disponibles para el handler
+func (c *Context) Set(key string, value interface{}) {
+	if c.values == nil {
+		c.values = make(map[string]interface{})
+	}
+	c.values[key] = value
+}
+
+// Get obtiene un valor guardado con Set
+func (c *Context) Get(key string) (interface{}, bool) {
+	value, ok := c.values[key]
+	return value, ok
+}
+
+// Derive crea una copia del Context con otro context.Context y otro
+// writer, para ejecutar el handler en otra goroutine sin compartir estado:
+// copia el usuario, los valores y el ErrorHandler, y su Request lleva ctx.
+// Los cambios en la copia no se reflejan en el original.
+func (c *Context) Derive(ctx context.Context, w http.ResponseWriter) *Context {
+	derived := &Context{
+		RWriter:      w,
+		Request:      c.Request.WithContext(ctx),
+		Ctx:          ctx,
+		userID:       c.userID,
+		errorHandler: c.errorHandler,
+	}
+	if c.values != nil {
+		derived.values = make(map[string]interface{}, len(c.values))
+		for key, value := range c.values {
+			derived.values[key] = value
+		}
+	}
+	derived.timedOut.Store(c.TimedOut())
+	return derived
+}
+
 // Context retorna el context.Context subyacente
 func (c *Context) Context() context.Context {
 	return c.Ctx
 }
+
+// MarkTimedOut marca la petición como expirada: a partir de ese momento
+// las escrituras del handler se ignoran
+func (c *Context) MarkTimedOut() {
+	c.timedOut.Store(true)
+}
+
+// TimedOut indica si la petición superó su timeout
+func (c *Context) TimedOut() bool {
+	return c.timedOut.Load()
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gopost-api/apperrors"
+)
+
+// ErrorResponse es el cuerpo JSON de todas las respuestas de error
+type ErrorResponse struct {
+	Error   string                 `json:"error"`
+	Message string                 `json:"message"`
+	Code    int                    `json:"code"`
+	Fields  []apperrors.FieldError `json:"fields,omitempty"`
+}
+
+// HandlerFunc es un handler que devuelve un error en lugar de escribirlo.
+// Se registra con Handler, que delega el error en el ErrorHandler de la App.
+type HandlerFunc func(c *Context) error
+
+// ErrorHandler escribe la respuesta correspondiente a un error
+type ErrorHandler func(c *Context, err error)
+
+// StatusCoder lo implementan los errores que ya conocen su código HTTP
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// Handler adapta un HandlerFunc para poder registrarlo como ruta o pasarlo
+// por middlewares
+func Handler(h HandlerFunc) HandleFunc {
+	return func(c *Context) {
+		if err := h(c); err != nil {
+			c.Error(err)
+		}
+	}
+}
+
+// HTTPError es un error que conoce el código HTTP con el que debe responderse
+type HTTPError struct {
+	Code    int
+	Message string
+}
+
+func (e *HTTPError) Error() string {
+	return e.Message
+}
+
+func (e *HTTPError) StatusCode() int {
+	return e.Code
+}
+
+func NewHTTPError(code int, message string) *HTTPError {
+	return &HTTPError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// invalidParam construye el error 400 que devuelven los helpers de parámetros
+func invalidParam(kind, name, expected string) *HTTPError {
+	return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("El %s '%s' debe ser %s", kind, name, expected))
+}
+
+// StatusFor traduce un error al código HTTP que le corresponde
+func StatusFor(err error) int {
+	var coder StatusCoder
+	switch {
+	case errors.As(err, &coder):
+		return coder.StatusCode()
+	case errors.Is(err, apperrors.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, apperrors.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, apperrors.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apperrors.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// DefaultErrorHandler responde con un ErrorResponse usando el código de
+// StatusFor. Los errores 5xx se registran y, salvo que traigan su propio
+// código como el 503 de TimeoutMiddleware, se responden con un mensaje
+// genérico para no exponer detalles internos.
+func DefaultErrorHandler(c *Context, err error) {
+	code := StatusFor(err)
+	message := err.Error()
+	if code >= http.StatusInternalServerError {
+		log.Printf("error en %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		var coder StatusCoder
+		if !errors.As(err, &coder) {
+			message = "Error interno del servidor"
+		}
+	}
+
+	// Los errores de validación informan además qué campos fallaron
+	var fields []apperrors.FieldError
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		fields = appErr.Fields
+	}
+
+	c.RWriter.Header().Set("Content-Type", "application/json")
+	c.RWriter.WriteHeader(code)
+	json.NewEncoder(c.RWriter).Encode(ErrorResponse{
+		Error:   http.StatusText(code),
+		Message: message,
+		Code:    code,
+		Fields:  fields,
+	})
+}
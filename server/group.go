@@ -0,0 +1,75 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Group agrupa rutas bajo un mismo prefijo y una misma pila de middlewares.
+// Los grupos se pueden anidar: un subgrupo hereda el prefijo y los
+// middlewares de su padre y agrega los suyos a continuación.
+type Group struct {
+	app         *App
+	prefix      string
+	middlewares []Middleware
+}
+
+// Group crea un subgrupo con el prefijo y los middlewares indicados
+func (g *Group) Group(prefix string, middlewares ...Middleware) *Group {
+	stack := make([]Middleware, 0, len(g.middlewares)+len(middlewares))
+	stack = append(stack, g.middlewares...)
+	stack = append(stack, middlewares...)
+
+	return &Group{
+		app:         g.app,
+		prefix:      joinPaths(g.prefix, prefix),
+		middlewares: stack,
+	}
+}
+
+// Use agrega middlewares al grupo; afectan a las rutas registradas después
+func (g *Group) Use(middlewares ...Middleware) {
+	g.middlewares = append(g.middlewares, middlewares...)
+}
+
+func (g *Group) Get(path string, handler HandleFunc, middlewares ...Middleware) {
+	g.handle(http.MethodGet, path, Chain(handler, middlewares...))
+}
+
+func (g *Group) Post(path string, handler HandleFunc, middlewares ...Middleware) {
+	g.handle(http.MethodPost, path, Chain(handler, middlewares...))
+}
+
+func (g *Group) Put(path string, handler HandleFunc, middlewares ...Middleware) {
+	g.handle(http.MethodPut, path, Chain(handler, middlewares...))
+}
+
+func (g *Group) Patch(path string, handler HandleFunc, middlewares ...Middleware) {
+	g.handle(http.MethodPatch, path, Chain(handler, middlewares...))
+}
+
+func (g *Group) Delete(path string, handler HandleFunc, middlewares ...Middleware) {
+	g.handle(http.MethodDelete, path, Chain(handler, middlewares...))
+}
+
+// handle aplica los middlewares del grupo y registra la ruta completa en la App.
+// Los middlewares se ejecutan en el orden en que fueron declarados.
+func (g *Group) handle(method, path string, handler HandleFunc) {
+	g.app.handle(method, joinPaths(g.prefix, path), Chain(handler, g.middlewares...))
+}
+
+// joinPaths une un prefijo y una ruta evitando barras duplicadas.
+// Una ruta vacía o "/" apunta exactamente al prefijo.
+func joinPaths(prefix, path string) string {
+	prefix = strings.TrimRight(prefix, "/")
+	if path == "" || path == "/" {
+		if prefix == "" {
+			return "/"
+		}
+		return prefix
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return prefix + path
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// trace devuelve un middleware que anota su nombre antes de seguir la cadena
+func trace(calls *[]string, name string) Middleware {
+	return func(next HandleFunc) HandleFunc {
+		return func(c *Context) {
+			*calls = append(*calls, name)
+			next(c)
+		}
+	}
+}
+
+func TestJoinPaths(t *testing.T) {
+	tests := []struct {
+		prefix, path, want string
+	}{
+		{prefix: "/api", path: "/posts", want: "/api/posts"},
+		{prefix: "/api/", path: "/posts", want: "/api/posts"},
+		{prefix: "/api", path: "posts", want: "/api/posts"},
+		{prefix: "/api", path: "", want: "/api"},
+		{prefix: "/api", path: "/", want: "/api"},
+		{prefix: "", path: "/", want: "/"},
+		{prefix: "/", path: "", want: "/"},
+		{prefix: "", path: "/posts/{id}", want: "/posts/{id}"},
+	}
+
+	for _, tt := range tests {
+		if got := joinPaths(tt.prefix, tt.path); got != tt.want {
+			t.Errorf("joinPaths(%q, %q) = %q, se esperaba %q", tt.prefix, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestGroupPrefix(t *testing.T) {
+	app := New()
+	api := app.Group("/api/")
+	api.Get("/", func(c *Context) { c.Send("raíz") })
+	v1 := api.Group("v1")
+	v1.Get("/posts/{id}", func(c *Context) { c.Send("post " + c.Param("id")) })
+	v1.Group("/admin").Delete("users/{id}", func(c *Context) { c.Send("admin " + c.Param("id")) })
+
+	tests := []struct {
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{method: http.MethodGet, path: "/api", wantStatus: http.StatusOK, wantBody: "raíz"},
+		{method: http.MethodGet, path: "/api/v1/posts/7", wantStatus: http.StatusOK, wantBody: "post 7"},
+		{method: http.MethodDelete, path: "/api/v1/admin/users/3", wantStatus: http.StatusOK, wantBody: "admin 3"},
+		{method: http.MethodGet, path: "/v1/posts/7", wantStatus: http.StatusNotFound},
+		{method: http.MethodGet, path: "/api/posts/7", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, se esperaba %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("cuerpo = %q, se esperaba %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestGroupMiddlewareOrder(t *testing.T) {
+	var calls []string
+	app := New()
+	app.Use(trace(&calls, "global"))
+
+	api := app.Group("/api", trace(&calls, "api"))
+	api.Get("/antes", func(c *Context) { calls = append(calls, "handler") })
+	api.Use(trace(&calls, "api.Use"))
+
+	admin := api.Group("/admin", trace(&calls, "admin"))
+	admin.Use(trace(&calls, "admin.Use"))
+	admin.Get("/users", func(c *Context) { calls = append(calls, "handler") }, trace(&calls, "ruta"))
+	// Un Use en el padre después de crear el subgrupo no lo alcanza
+	api.Use(trace(&calls, "api.Use tardío"))
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		// Use solo afecta a las rutas registradas después
+		{path: "/api/antes", want: []string{"global", "api", "handler"}},
+		{path: "/api/admin/users", want: []string{"global", "api", "api.Use", "admin", "admin.Use", "ruta", "handler"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			calls = nil
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, se esperaba %d", rec.Code, http.StatusOK)
+			}
+			if got := strings.Join(calls, " > "); got != strings.Join(tt.want, " > ") {
+				t.Errorf("orden = %s, se esperaba %s", got, strings.Join(tt.want, " > "))
+			}
+		})
+	}
+}
+
+func TestGroupDoesNotShareMiddlewares(t *testing.T) {
+	var calls []string
+	app := New()
+	api := app.Group("/api")
+	a := api.Group("/a", trace(&calls, "a"))
+	b := api.Group("/b", trace(&calls, "b"))
+	a.Get("/x", func(c *Context) { calls = append(calls, "handler a") })
+	b.Get("/x", func(c *Context) { calls = append(calls, "handler b") })
+
+	for path, want := range map[string]string{"/api/a/x": "a > handler a", "/api/b/x": "b > handler b"} {
+		calls = nil
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		if got := strings.Join(calls, " > "); got != want {
+			t.Errorf("%s: orden = %s, se esperaba %s", path, got, want)
+		}
+	}
+}
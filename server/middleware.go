@@ -0,0 +1,22 @@
+package server
+
+// Middleware decora un HandleFunc con comportamiento adicional.
+// Un middleware recibe el siguiente handler de la cadena (next) y decide
+// si lo invoca, antes o después de ejecutar su propia lógica.
+type Middleware func(HandleFunc) HandleFunc
+
+// Chain compone los middlewares alrededor del handler respetando el orden
+// declarado: el primero de la lista es el primero en ejecutarse.
+func Chain(handler HandleFunc, middlewares ...Middleware) HandleFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// Use registra middlewares globales que se ejecutan en cada petición,
+// antes de resolver la ruta, en el orden en que fueron declarados.
+func (a *App) Use(middlewares ...Middleware) {
+	a.middlewares = append(a.middlewares, middlewares...)
+	a.handler = Chain(a.dispatch, a.middlewares...)
+}
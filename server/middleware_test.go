@@ -0,0 +1,104 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// around anota su nombre antes y después de ejecutar el resto de la cadena
+func around(calls *[]string, name string) Middleware {
+	return func(next HandleFunc) HandleFunc {
+		return func(c *Context) {
+			*calls = append(*calls, name+" antes")
+			next(c)
+			*calls = append(*calls, name+" después")
+		}
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	var calls []string
+	handler := func(c *Context) { calls = append(calls, "handler") }
+
+	tests := []struct {
+		name        string
+		middlewares []Middleware
+		want        []string
+	}{
+		{name: "sin middlewares", want: []string{"handler"}},
+		{
+			name:        "el primero envuelve a los demás",
+			middlewares: []Middleware{around(&calls, "m1"), around(&calls, "m2"), around(&calls, "m3")},
+			want:        []string{"m1 antes", "m2 antes", "m3 antes", "handler", "m3 después", "m2 después", "m1 después"},
+		},
+		{
+			name: "un middleware que no llama a next corta la cadena",
+			middlewares: []Middleware{
+				around(&calls, "m1"),
+				func(next HandleFunc) HandleFunc {
+					return func(c *Context) { calls = append(calls, "corte") }
+				},
+				around(&calls, "m3"),
+			},
+			want: []string{"m1 antes", "corte", "m1 después"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			Chain(handler, tt.middlewares...)(&Context{})
+
+			if got := strings.Join(calls, " > "); got != strings.Join(tt.want, " > ") {
+				t.Errorf("orden = %s, se esperaba %s", got, strings.Join(tt.want, " > "))
+			}
+		})
+	}
+}
+
+func TestUseOrder(t *testing.T) {
+	var calls []string
+	app := New()
+	app.Use(around(&calls, "g1"), around(&calls, "g2"))
+	app.Get("/posts", func(c *Context) {
+		calls = append(calls, "handler")
+		c.Status(http.StatusNoContent)
+	}, around(&calls, "ruta"))
+	// Los middlewares globales alcanzan también a las rutas ya registradas
+	app.Use(around(&calls, "g3"))
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		want       []string
+	}{
+		{
+			path:       "/posts",
+			wantStatus: http.StatusNoContent,
+			want:       []string{"g1 antes", "g2 antes", "g3 antes", "ruta antes", "handler", "ruta después", "g3 después", "g2 después", "g1 después"},
+		},
+		// Corren antes de resolver la ruta, así que también ven los 404
+		{
+			path:       "/nada",
+			wantStatus: http.StatusNotFound,
+			want:       []string{"g1 antes", "g2 antes", "g3 antes", "g3 después", "g2 después", "g1 después"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			calls = nil
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, se esperaba %d", rec.Code, tt.wantStatus)
+			}
+			if got := strings.Join(calls, " > "); got != strings.Join(tt.want, " > ") {
+				t.Errorf("orden = %s, se esperaba %s", got, strings.Join(tt.want, " > "))
+			}
+		})
+	}
+}
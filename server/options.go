@@ -0,0 +1,93 @@
+package server
+
+import (
+	"time"
+
+	"github.com/gopost-api/config"
+)
+
+// Option configura la App al crearla con New
+type Option func(*App)
+
+// WithConfig aplica la configuración cargada desde el entorno.
+// Los valores vacíos o en cero conservan los valores por defecto.
+func WithConfig(cfg *config.Config) Option {
+	return func(a *App) {
+		if cfg == nil {
+			return
+		}
+		for _, opt := range []Option{
+			WithAddr(cfg.Port),
+			WithReadTimeout(cfg.ReadTimeout),
+			WithWriteTimeout(cfg.WriteTimeout),
+			WithIdleTimeout(cfg.IdleTimeout),
+			WithMaxHeaderBytes(cfg.MaxHeaderBytes),
+			WithShutdownTimeout(cfg.ShutdownTimeout),
+		} {
+			opt(a)
+		}
+	}
+}
+
+// WithAddr define la dirección en la que escucha el servidor, por ejemplo ":8080"
+func WithAddr(addr string) Option {
+	return func(a *App) {
+		if addr != "" {
+			a.addr = addr
+		}
+	}
+}
+
+// WithReadTimeout define el tiempo máximo para leer la petición completa
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(a *App) {
+		if timeout > 0 {
+			a.readTimeout = timeout
+		}
+	}
+}
+
+// WithWriteTimeout define el tiempo máximo para escribir la respuesta
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(a *App) {
+		if timeout > 0 {
+			a.writeTimeout = timeout
+		}
+	}
+}
+
+// WithIdleTimeout define cuánto se mantiene abierta una conexión keep-alive inactiva
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(a *App) {
+		if timeout > 0 {
+			a.idleTimeout = timeout
+		}
+	}
+}
+
+// WithMaxHeaderBytes define el tamaño máximo de las cabeceras de la petición
+func WithMaxHeaderBytes(n int) Option {
+	return func(a *App) {
+		if n > 0 {
+			a.maxHeaderBytes = n
+		}
+	}
+}
+
+// WithShutdownTimeout define cuánto se espera a que terminen las peticiones en curso al apagar
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(a *App) {
+		if timeout > 0 {
+			a.shutdownTimeout = timeout
+		}
+	}
+}
+
+// WithErrorHandler reemplaza el ErrorHandler que traduce los errores de los handlers
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(a *App) {
+		if handler != nil {
+			a.errorHandler = handler
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gopost-api/config"
+)
+
+// serverSettings son los valores de la App que terminan en el http.Server
+type serverSettings struct {
+	addr            string
+	readTimeout     time.Duration
+	writeTimeout    time.Duration
+	idleTimeout     time.Duration
+	maxHeaderBytes  int
+	shutdownTimeout time.Duration
+}
+
+func settingsOf(a *App) serverSettings {
+	srv := a.httpServer()
+	return serverSettings{
+		addr:            srv.Addr,
+		readTimeout:     srv.ReadTimeout,
+		writeTimeout:    srv.WriteTimeout,
+		idleTimeout:     srv.IdleTimeout,
+		maxHeaderBytes:  srv.MaxHeaderBytes,
+		shutdownTimeout: a.shutdownTimeout,
+	}
+}
+
+func TestOptions(t *testing.T) {
+	defaults := serverSettings{
+		addr:            DefaultAddr,
+		readTimeout:     DefaultReadTimeout,
+		writeTimeout:    DefaultWriteTimeout,
+		idleTimeout:     DefaultIdleTimeout,
+		maxHeaderBytes:  http.DefaultMaxHeaderBytes,
+		shutdownTimeout: DefaultShutdownTimeout,
+	}
+	with := func(change func(s *serverSettings)) serverSettings {
+		s := defaults
+		change(&s)
+		return s
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want serverSettings
+	}{
+		{name: "sin opciones", want: defaults},
+		{
+			name: "valores en cero conservan los por defecto",
+			opts: []Option{
+				WithAddr(""), WithReadTimeout(0), WithWriteTimeout(-time.Second), WithIdleTimeout(0),
+				WithMaxHeaderBytes(0), WithShutdownTimeout(0), WithConfig(nil), WithConfig(&config.Config{}),
+			},
+			want: defaults,
+		},
+		{name: "WithAddr", opts: []Option{WithAddr(":9090")}, want: with(func(s *serverSettings) { s.addr = ":9090" })},
+		{name: "WithReadTimeout", opts: []Option{WithReadTimeout(3 * time.Second)}, want: with(func(s *serverSettings) { s.readTimeout = 3 * time.Second })},
+		{name: "WithWriteTimeout", opts: []Option{WithWriteTimeout(4 * time.Second)}, want: with(func(s *serverSettings) { s.writeTimeout = 4 * time.Second })},
+		{name: "WithIdleTimeout", opts: []Option{WithIdleTimeout(5 * time.Second)}, want: with(func(s *serverSettings) { s.idleTimeout = 5 * time.Second })},
+		{name: "WithMaxHeaderBytes", opts: []Option{WithMaxHeaderBytes(4096)}, want: with(func(s *serverSettings) { s.maxHeaderBytes = 4096 })},
+		{name: "WithShutdownTimeout", opts: []Option{WithShutdownTimeout(6 * time.Second)}, want: with(func(s *serverSettings) { s.shutdownTimeout = 6 * time.Second })},
+		{
+			name: "WithConfig",
+			opts: []Option{WithConfig(&config.Config{
+				Port: ":7070", ReadTimeout: time.Second, WriteTimeout: 2 * time.Second, IdleTimeout: 3 * time.Second,
+				MaxHeaderBytes: 2048, ShutdownTimeout: 4 * time.Second,
+			})},
+			want: serverSettings{
+				addr: ":7070", readTimeout: time.Second, writeTimeout: 2 * time.Second, idleTimeout: 3 * time.Second,
+				maxHeaderBytes: 2048, shutdownTimeout: 4 * time.Second,
+			},
+		},
+		{
+			name: "WithConfig parcial conserva el resto",
+			opts: []Option{WithConfig(&config.Config{Port: ":7070", IdleTimeout: 3 * time.Second})},
+			want: with(func(s *serverSettings) { s.addr, s.idleTimeout = ":7070", 3*time.Second }),
+		},
+		{
+			name: "la última opción gana",
+			opts: []Option{WithConfig(&config.Config{ReadTimeout: time.Second}), WithReadTimeout(9 * time.Second)},
+			want: with(func(s *serverSettings) { s.readTimeout = 9 * time.Second }),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := settingsOf(New(tt.opts...)); got != tt.want {
+				t.Errorf("configuración = %+v, se esperaba %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithErrorHandler(t *testing.T) {
+	var called bool
+	custom := func(c *Context, err error) {
+		called = true
+		c.RWriter.WriteHeader(http.StatusTeapot)
+	}
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantCustom bool
+		wantStatus int
+	}{
+		{name: "por defecto", wantStatus: http.StatusNotFound},
+		{name: "nil conserva el por defecto", opts: []Option{WithErrorHandler(nil)}, wantStatus: http.StatusNotFound},
+		{name: "propio", opts: []Option{WithErrorHandler(custom)}, wantCustom: true, wantStatus: http.StatusTeapot},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			rec := httptest.NewRecorder()
+			New(tt.opts...).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nada", nil))
+
+			if called != tt.wantCustom || rec.Code != tt.wantStatus {
+				t.Errorf("ErrorHandler propio llamado: %t, status %d; se esperaba %t, %d", called, rec.Code, tt.wantCustom, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"strconv"
+	"time"
+)
+
+// Param obtiene el valor de un parámetro de ruta declarado como {name}
+func (c *Context) Param(name string) string {
+	return c.Request.PathValue(name)
+}
+
+// ParamInt obtiene un parámetro de ruta como entero
+func (c *Context) ParamInt(name string) (int, error) {
+	value, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		return 0, invalidParam("parámetro", name, "un número entero")
+	}
+	return value, nil
+}
+
+// ParamUint obtiene un parámetro de ruta como entero positivo, por ejemplo un ID
+func (c *Context) ParamUint(name string) (uint, error) {
+	value, err := strconv.ParseUint(c.Param(name), 10, strconv.IntSize)
+	if err != nil {
+		return 0, invalidParam("parámetro", name, "un número entero positivo")
+	}
+	return uint(value), nil
+}
+
+// Query obtiene un parámetro de la query string o el valor por defecto si no viene
+func (c *Context) Query(name, defaultValue string) string {
+	value := c.Request.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// QueryInt obtiene un parámetro de la query string como entero
+func (c *Context) QueryInt(name string, defaultValue int) (int, error) {
+	raw := c.Request.URL.Query().Get(name)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, invalidParam("query param", name, "un número entero")
+	}
+	return value, nil
+}
+
+// QueryUint obtiene un parámetro de la query string como entero positivo
+func (c *Context) QueryUint(name string, defaultValue uint) (uint, error) {
+	raw := c.Request.URL.Query().Get(name)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.ParseUint(raw, 10, strconv.IntSize)
+	if err != nil {
+		return 0, invalidParam("query param", name, "un número entero positivo")
+	}
+	return uint(value), nil
+}
+
+// QueryBool obtiene un parámetro de la query string como booleano (true/false, 1/0)
+func (c *Context) QueryBool(name string, defaultValue bool) (bool, error) {
+	raw := c.Request.URL.Query().Get(name)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, invalidParam("query param", name, "true o false")
+	}
+	return value, nil
+}
+
+// QueryTime obtiene un parámetro de la query string en formato RFC 3339
+// (por ejemplo 2024-01-31T10:00:00Z). Devuelve nil si no viene.
+func (c *Context) QueryTime(name string) (*time.Time, error) {
+	raw := c.Request.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, invalidParam("query param", name, "una fecha RFC 3339, por ejemplo 2024-01-31T10:00:00Z")
+	}
+	return &value, nil
+}
+
+// HasQuery indica si el parámetro viene en la query string, aunque esté vacío
+func (c *Context) HasQuery(name string) bool {
+	return c.Request.URL.Query().Has(name)
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// serveParam registra route con un handler que responde el valor que
+// devuelve get, o su error a través del ErrorHandler
+func serveParam(t *testing.T, route, target string, get func(c *Context) (any, error)) *httptest.ResponseRecorder {
+	t.Helper()
+	app := New()
+	app.Get(route, Handler(func(c *Context) error {
+		value, err := get(c)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, map[string]string{"value": fmt.Sprint(value)})
+	}))
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestParamHelpers(t *testing.T) {
+	paramInt := func(c *Context) (any, error) { return c.ParamInt("id") }
+	paramUint := func(c *Context) (any, error) { return c.ParamUint("id") }
+	// La ruta no declara {id}: el parámetro falta
+	missing := func(c *Context) (any, error) { return c.ParamUint("id") }
+
+	tests := []struct {
+		name       string
+		route      string
+		target     string
+		get        func(c *Context) (any, error)
+		wantStatus int
+		wantValue  string
+	}{
+		{name: "ParamInt válido", route: "/posts/{id}", target: "/posts/-7", get: paramInt, wantStatus: http.StatusOK, wantValue: "-7"},
+		{name: "ParamInt no numérico", route: "/posts/{id}", target: "/posts/abc", get: paramInt, wantStatus: http.StatusBadRequest},
+		{name: "ParamInt desbordado", route: "/posts/{id}", target: "/posts/9223372036854775808", get: paramInt, wantStatus: http.StatusBadRequest},
+		{name: "ParamUint válido", route: "/posts/{id}", target: "/posts/42", get: paramUint, wantStatus: http.StatusOK, wantValue: "42"},
+		{name: "ParamUint máximo", route: "/posts/{id}", target: "/posts/18446744073709551615", get: paramUint, wantStatus: http.StatusOK, wantValue: "18446744073709551615"},
+		{name: "ParamUint negativo", route: "/posts/{id}", target: "/posts/-1", get: paramUint, wantStatus: http.StatusBadRequest},
+		{name: "ParamUint no numérico", route: "/posts/{id}", target: "/posts/1a", get: paramUint, wantStatus: http.StatusBadRequest},
+		{name: "ParamUint desbordado", route: "/posts/{id}", target: "/posts/18446744073709551616", get: paramUint, wantStatus: http.StatusBadRequest},
+		{name: "ParamUint faltante", route: "/posts", target: "/posts", get: missing, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertParamResponse(t, serveParam(t, tt.route, tt.target, tt.get), tt.wantStatus, tt.wantValue)
+		})
+	}
+}
+
+func TestQueryHelpers(t *testing.T) {
+	queryInt := func(c *Context) (any, error) { return c.QueryInt("page", 1) }
+	queryUint := func(c *Context) (any, error) { return c.QueryUint("user_id", 0) }
+	queryBool := func(c *Context) (any, error) { return c.QueryBool("published", true) }
+	queryTime := func(c *Context) (any, error) {
+		value, err := c.QueryTime("since")
+		if err != nil || value == nil {
+			return "nil", err
+		}
+		return value.UTC().Format("2006-01-02T15:04:05Z"), nil
+	}
+	query := func(c *Context) (any, error) { return c.Query("sort", "created_at"), nil }
+
+	tests := []struct {
+		name       string
+		target     string
+		get        func(c *Context) (any, error)
+		wantStatus int
+		wantValue  string
+	}{
+		{name: "Query faltante usa el valor por defecto", target: "/posts", get: query, wantStatus: http.StatusOK, wantValue: "created_at"},
+		{name: "Query vacío usa el valor por defecto", target: "/posts?sort=", get: query, wantStatus: http.StatusOK, wantValue: "created_at"},
+		{name: "Query presente", target: "/posts?sort=title", get: query, wantStatus: http.StatusOK, wantValue: "title"},
+		{name: "QueryInt faltante", target: "/posts", get: queryInt, wantStatus: http.StatusOK, wantValue: "1"},
+		{name: "QueryInt válido", target: "/posts?page=3", get: queryInt, wantStatus: http.StatusOK, wantValue: "3"},
+		{name: "QueryInt no numérico", target: "/posts?page=tres", get: queryInt, wantStatus: http.StatusBadRequest},
+		{name: "QueryInt desbordado", target: "/posts?page=99999999999999999999", get: queryInt, wantStatus: http.StatusBadRequest},
+		{name: "QueryUint faltante", target: "/posts", get: queryUint, wantStatus: http.StatusOK, wantValue: "0"},
+		{name: "QueryUint válido", target: "/posts?user_id=5", get: queryUint, wantStatus: http.StatusOK, wantValue: "5"},
+		{name: "QueryUint negativo", target: "/posts?user_id=-5", get: queryUint, wantStatus: http.StatusBadRequest},
+		{name: "QueryUint desbordado", target: "/posts?user_id=18446744073709551616", get: queryUint, wantStatus: http.StatusBadRequest},
+		{name: "QueryBool faltante", target: "/posts", get: queryBool, wantStatus: http.StatusOK, wantValue: "true"},
+		{name: "QueryBool válido", target: "/posts?published=0", get: queryBool, wantStatus: http.StatusOK, wantValue: "false"},
+		{name: "QueryBool inválido", target: "/posts?published=quizas", get: queryBool, wantStatus: http.StatusBadRequest},
+		{name: "QueryTime faltante", target: "/posts", get: queryTime, wantStatus: http.StatusOK, wantValue: "nil"},
+		{name: "QueryTime válido", target: "/posts?since=2024-01-31T10:00:00%2B02:00", get: queryTime, wantStatus: http.StatusOK, wantValue: "2024-01-31T08:00:00Z"},
+		{name: "QueryTime inválido", target: "/posts?since=2024-01-31", get: queryTime, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertParamResponse(t, serveParam(t, "/posts", tt.target, tt.get), tt.wantStatus, tt.wantValue)
+		})
+	}
+}
+
+func assertParamResponse(t *testing.T, rec *httptest.ResponseRecorder, wantStatus int, wantValue string) {
+	t.Helper()
+	if rec.Code != wantStatus {
+		t.Fatalf("status = %d, se esperaba %d: %s", rec.Code, wantStatus, rec.Body.String())
+	}
+
+	if wantStatus != http.StatusOK {
+		var body ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Code != wantStatus || body.Message == "" {
+			t.Errorf("cuerpo = %q, se esperaba un ErrorResponse %d con mensaje", rec.Body.String(), wantStatus)
+		}
+		return
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("cuerpo no es JSON: %q", rec.Body.String())
+	}
+	if body["value"] != wantValue {
+		t.Errorf("valor = %q, se esperaba %q", body["value"], wantValue)
+	}
+}
@@ -1,49 +1,104 @@
 package server
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 type HandleFunc func(c *Context)
 
-func (a *App) Get(path string, handler HandleFunc) {
-	a.mux.HandleFunc("GET "+path, func(w http.ResponseWriter, r *http.Request) {
-		handler(&Context{
-			RWriter: w,
-			Request: r,
-			Ctx:     r.Context(),
-		})
-	})
-	a.handlerCount++
+// contextKey es la clave con la que el Context viaja dentro del request
+// desde la cadena global de middlewares hasta el handler de la ruta.
+type contextKey struct{}
+
+func (a *App) Get(path string, handler HandleFunc, middlewares ...Middleware) {
+	a.handle(http.MethodGet, path, Chain(handler, middlewares...))
 }
 
-func (a *App) Post(path string, handler HandleFunc) {
-	a.mux.HandleFunc("POST "+path, func(w http.ResponseWriter, r *http.Request) {
-		handler(&Context{
-			RWriter: w,
-			Request: r,
-			Ctx:     r.Context(),
-		})
-	})
-	a.handlerCount++
+func (a *App) Post(path string, handler HandleFunc, middlewares ...Middleware) {
+	a.handle(http.MethodPost, path, Chain(handler, middlewares...))
+}
+
+func (a *App) Put(path string, handler HandleFunc, middlewares ...Middleware) {
+	a.handle(http.MethodPut, path, Chain(handler, middlewares...))
+}
+
+func (a *App) Patch(path string, handler HandleFunc, middlewares ...Middleware) {
+	a.handle(http.MethodPatch, path, Chain(handler, middlewares...))
 }
 
-func (a *App) Put(path string, handler HandleFunc) {
-	a.mux.HandleFunc("PUT "+path, func(w http.ResponseWriter, r *http.Request) {
-		handler(&Context{
-			RWriter: w,
-			Request: r,
-			Ctx:     r.Context(),
-		})
+func (a *App) Delete(path string, handler HandleFunc, middlewares ...Middleware) {
+	a.handle(http.MethodDelete, path, Chain(handler, middlewares...))
+}
+
+// Group crea un grupo de rutas que comparten el prefijo y los middlewares indicados
+func (a *App) Group(prefix string, middlewares ...Middleware) *Group {
+	return &Group{
+		app:         a,
+		prefix:      prefix,
+		middlewares: append([]Middleware(nil), middlewares...),
+	}
+}
+
+// ServeHTTP implementa http.Handler: crea el Context de la petición y lo
+// pasa por la cadena de middlewares globales antes de resolver la ruta.
+func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.handler(&Context{
+		RWriter:      w,
+		Request:      r,
+		Ctx:          r.Context(),
+		errorHandler: a.errorHandler,
 	})
-	a.handlerCount++
 }
 
-func (a *App) Delete(path string, handler HandleFunc) {
-	a.mux.HandleFunc("DELETE "+path, func(w http.ResponseWriter, r *http.Request) {
-		handler(&Context{
-			RWriter: w,
-			Request: r,
-			Ctx:     r.Context(),
-		})
+// dispatch es el último eslabón de la cadena global: entrega la petición
+// al mux para que ejecute el handler de la ruta con el mismo Context.
+func (a *App) dispatch(c *Context) {
+	c.Request = c.Request.WithContext(context.WithValue(c.Ctx, contextKey{}, c))
+
+	// Sin patrón el mux respondería en texto plano con 404 o 405: esos
+	// errores pasan por el ErrorHandler como el resto
+	if h, pattern := a.mux.Handler(c.Request); pattern == "" {
+		a.routeError(c, h)
+		return
+	}
+	a.mux.ServeHTTP(c.RWriter, c.Request)
+}
+
+// routeError ejecuta el handler de error del mux sobre un writer que solo
+// registra el código y la cabecera Allow, y responde con el ErrorHandler
+func (a *App) routeError(c *Context, h http.Handler) {
+	recorder := &routeErrorRecorder{header: make(http.Header), code: http.StatusNotFound}
+	h.ServeHTTP(recorder, c.Request)
+
+	switch recorder.code {
+	case http.StatusMethodNotAllowed:
+		c.RWriter.Header().Set("Allow", recorder.header.Get("Allow"))
+		c.Error(NewHTTPError(http.StatusMethodNotAllowed, "Método no permitido para esta ruta"))
+	default:
+		c.Error(NewHTTPError(http.StatusNotFound, "Ruta no encontrada"))
+	}
+}
+
+// routeErrorRecorder descarta el cuerpo de texto plano de los errores del mux
+type routeErrorRecorder struct {
+	header http.Header
+	code   int
+}
+
+func (r *routeErrorRecorder) Header() http.Header         { return r.header }
+func (r *routeErrorRecorder) Write(p []byte) (int, error) { return len(p), nil }
+func (r *routeErrorRecorder) WriteHeader(code int)        { r.code = code }
+
+// handle registra el handler en el mux con el patrón "MÉTODO /ruta"
+func (a *App) handle(method, path string, handler HandleFunc) {
+	a.mux.HandleFunc(method+" "+path, func(w http.ResponseWriter, r *http.Request) {
+		c, ok := r.Context().Value(contextKey{}).(*Context)
+		if !ok {
+			c = &Context{RWriter: w, Ctx: r.Context(), errorHandler: a.errorHandler}
+		}
+		c.Request = r
+		handler(c)
 	})
 	a.handlerCount++
 }
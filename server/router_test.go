@@ -0,0 +1,70 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnmatchedRoutesUseErrorHandler(t *testing.T) {
+	app := New()
+	app.Get("/posts/{id}", func(c *Context) {
+		c.JSON(http.StatusOK, map[string]string{"id": c.Request.PathValue("id")})
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantAllow  string
+	}{
+		{name: "ruta existente", method: http.MethodGet, path: "/posts/7", wantStatus: http.StatusOK},
+		{name: "ruta inexistente", method: http.MethodGet, path: "/nada", wantStatus: http.StatusNotFound},
+		{name: "método no permitido", method: http.MethodDelete, path: "/posts/7", wantStatus: http.StatusMethodNotAllowed, wantAllow: "GET, HEAD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, se esperaba %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, se esperaba JSON", got)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Allow = %q, se esperaba %q", got, tt.wantAllow)
+			}
+			if tt.wantStatus == http.StatusOK {
+				return
+			}
+
+			var body ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("cuerpo no es un ErrorResponse: %q", rec.Body.String())
+			}
+			if body.Code != tt.wantStatus {
+				t.Errorf("code = %d, se esperaba %d", body.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestUnmatchedRoutesUseCustomErrorHandler(t *testing.T) {
+	var handled error
+	app := New(WithErrorHandler(func(c *Context, err error) {
+		handled = err
+		c.RWriter.WriteHeader(StatusFor(err))
+	}))
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nada", nil))
+
+	if handled == nil || rec.Code != http.StatusNotFound {
+		t.Fatalf("el ErrorHandler propio no recibió el 404 (status %d)", rec.Code)
+	}
+}
@@ -1,37 +1,158 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
+)
 
-	"github.com/gopost-api/config"
+// Valores por defecto del servidor HTTP
+const (
+	DefaultAddr            = ":8080"
+	DefaultReadTimeout     = 15 * time.Second
+	DefaultWriteTimeout    = 15 * time.Second
+	DefaultIdleTimeout     = 60 * time.Second
+	DefaultShutdownTimeout = 15 * time.Second
 )
 
+// ShutdownHook se ejecuta durante el apagado del servidor, después de
+// drenar las peticiones en curso (por ejemplo para cerrar la base de datos)
+type ShutdownHook func(ctx context.Context) error
+
 type App struct {
-	config       config.Config
+	addr         string
 	mux          *http.ServeMux
+	middlewares  []Middleware
+	handler      HandleFunc
+	errorHandler ErrorHandler
 	handlerCount int
+
+	readTimeout     time.Duration
+	writeTimeout    time.Duration
+	idleTimeout     time.Duration
+	maxHeaderBytes  int
+	shutdownTimeout time.Duration
+	shutdownHooks   []ShutdownHook
 }
 
-func New() *App {
-	return &App{
-		mux:          http.NewServeMux(),
-		handlerCount: 0,
+// New crea la aplicación con valores por defecto que pueden ajustarse con opciones
+func New(opts ...Option) *App {
+	a := &App{
+		addr:            DefaultAddr,
+		mux:             http.NewServeMux(),
+		errorHandler:    DefaultErrorHandler,
+		handlerCount:    0,
+		readTimeout:     DefaultReadTimeout,
+		writeTimeout:    DefaultWriteTimeout,
+		idleTimeout:     DefaultIdleTimeout,
+		maxHeaderBytes:  http.DefaultMaxHeaderBytes,
+		shutdownTimeout: DefaultShutdownTimeout,
 	}
+	a.handler = a.dispatch
+
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// OnShutdown registra funciones que se ejecutan al apagar el servidor.
+// Se ejecutan en orden inverso al de registro, como los defer.
+func (a *App) OnShutdown(hooks ...ShutdownHook) {
+	a.shutdownHooks = append(a.shutdownHooks, hooks...)
 }
 
+// RunServer inicia el servidor y lo apaga de forma ordenada al recibir SIGINT o SIGTERM
 func (a *App) RunServer() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return a.Run(ctx)
+}
+
+// Run inicia el servidor en la dirección configurada y lo mantiene activo
+// hasta que se cancele ctx; ver Serve
+func (a *App) Run(ctx context.Context) error {
 	// Mostrar el banner antes de iniciar el servidor
-	a.printBanner(a.config.Port)
+	a.printBanner(a.addr)
 
-	// Configurar servidor con timeouts
-	srv := &http.Server{
-		Addr:    a.config.Port,
-		Handler: a.mux,
+	ln, err := net.Listen("tcp", a.addr)
+	if err != nil {
+		// Los hooks se ejecutan igual, por ejemplo para cerrar la base de datos
+		return errors.Join(err, a.shutdown(ctx, nil))
 	}
+	return a.Serve(ctx, ln)
+}
+
+// Serve atiende las conexiones de ln hasta que se cancele ctx. Al
+// cancelarse deja de aceptar conexiones, espera a que terminen las
+// peticiones en curso (como máximo el shutdownTimeout) y ejecuta los hooks
+// registrados con OnShutdown.
+func (a *App) Serve(ctx context.Context, ln net.Listener) error {
+	srv := a.httpServer()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(ln)
+	}()
 
-	return srv.ListenAndServe()
+	var runErr error
+	select {
+	case err := <-serveErr:
+		// El servidor se detuvo por un error
+		if !errors.Is(err, http.ErrServerClosed) {
+			runErr = err
+		}
+	case <-ctx.Done():
+		log.Println("Apagando servidor...")
+	}
+
+	runErr = errors.Join(runErr, a.shutdown(ctx, srv))
+	if runErr == nil {
+		log.Println("✓ Servidor detenido correctamente")
+	}
+	return runErr
+}
+
+// httpServer configura el http.Server con los timeouts de la App
+func (a *App) httpServer() *http.Server {
+	return &http.Server{
+		Addr:           a.addr,
+		Handler:        a,
+		ReadTimeout:    a.readTimeout,
+		WriteTimeout:   a.writeTimeout,
+		IdleTimeout:    a.idleTimeout,
+		MaxHeaderBytes: a.maxHeaderBytes,
+	}
+}
+
+// shutdown drena las peticiones en curso de srv, si lo hay, y ejecuta los
+// hooks en orden inverso al de registro, todo dentro del shutdownTimeout
+func (a *App) shutdown(ctx context.Context, srv *http.Server) error {
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.shutdownTimeout)
+	defer cancel()
+
+	var err error
+	if srv != nil {
+		if shutdownErr := srv.Shutdown(shutdownCtx); shutdownErr != nil {
+			err = fmt.Errorf("error al drenar las peticiones en curso: %w", shutdownErr)
+		}
+	}
+
+	for i := len(a.shutdownHooks) - 1; i >= 0; i-- {
+		if hookErr := a.shutdownHooks[i](shutdownCtx); hookErr != nil {
+			err = errors.Join(err, hookErr)
+		}
+	}
+	return err
 }
 
 func (a *App) printBanner(addr string) {
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordHooks registra hooks que anotan su nombre en orden de ejecución y
+// devuelven el error indicado
+func recordHooks(a *App, calls *[]string, errs map[string]error, names ...string) {
+	for _, name := range names {
+		a.OnShutdown(func(ctx context.Context) error {
+			*calls = append(*calls, name)
+			return errs[name]
+		})
+	}
+}
+
+// startServe ejecuta Serve en 127.0.0.1:0 y devuelve la URL base y el
+// canal con el error de Serve
+func startServe(t *testing.T, ctx context.Context, a *App) (string, <-chan error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- a.Serve(ctx, ln) }()
+	return "http://" + ln.Addr().String(), done
+}
+
+func TestServeDrainsInFlightRequests(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	app := New(WithShutdownTimeout(5 * time.Second))
+	app.Get("/lenta", func(c *Context) {
+		close(started)
+		<-release
+		c.Send("terminada")
+	})
+
+	var calls []string
+	hookErr := errors.New("no se pudo cerrar la base")
+	recordHooks(app, &calls, map[string]error{"db": hookErr}, "db", "cache", "mailer")
+
+	baseURL, done := startServe(t, ctx, app)
+
+	type result struct {
+		body string
+		err  error
+	}
+	response := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(baseURL + "/lenta")
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		response <- result{body: string(body), err: err}
+	}()
+
+	<-started
+	cancel()
+
+	// El apagado espera a la petición en curso antes de ejecutar los hooks
+	select {
+	case err := <-done:
+		t.Fatalf("Serve terminó con una petición en curso: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if len(calls) != 0 {
+		t.Fatalf("los hooks corrieron antes de drenar la petición: %v", calls)
+	}
+	close(release)
+
+	if r := <-response; r.err != nil || r.body != "terminada" {
+		t.Fatalf("la petición en curso no terminó: %q, %v", r.body, r.err)
+	}
+	err := <-done
+	if !errors.Is(err, hookErr) {
+		t.Errorf("Serve() = %v, se esperaba el error del hook", err)
+	}
+	if got := strings.Join(calls, " > "); got != "mailer > cache > db" {
+		t.Errorf("orden de los hooks = %s, se esperaba mailer > cache > db", got)
+	}
+}
+
+func TestServeShutdownTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	app := New(WithShutdownTimeout(50 * time.Millisecond))
+	app.Get("/colgada", func(c *Context) {
+		close(started)
+		<-release
+	})
+
+	var hookCtxErr error
+	app.OnShutdown(func(ctx context.Context) error {
+		hookCtxErr = ctx.Err()
+		return nil
+	})
+
+	baseURL, done := startServe(t, ctx, app)
+	go http.Get(baseURL + "/colgada")
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Serve() = %v, se esperaba context.DeadlineExceeded", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve no respetó el shutdownTimeout")
+	}
+	// Los hooks corren igual, con el plazo ya vencido
+	if !errors.Is(hookCtxErr, context.DeadlineExceeded) {
+		t.Errorf("el hook recibió un context con error %v, se esperaba el plazo vencido", hookCtxErr)
+	}
+}
+
+func TestServeJoinsServeAndHookErrors(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	// Un listener cerrado hace fallar a Serve sin cancelar ctx
+	ln.Close()
+
+	var calls []string
+	hookErr := errors.New("no se pudo cerrar la base")
+	app := New()
+	recordHooks(app, &calls, map[string]error{"db": hookErr}, "db")
+
+	err = app.Serve(context.Background(), ln)
+	if !errors.Is(err, net.ErrClosed) || !errors.Is(err, hookErr) {
+		t.Errorf("Serve() = %v, se esperaban el error del listener y el del hook", err)
+	}
+	if len(calls) != 1 {
+		t.Errorf("hooks ejecutados = %v, se esperaba db", calls)
+	}
+}
+
+func TestRun(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	defer busy.Close()
+
+	tests := []struct {
+		name    string
+		addr    string
+		wantErr bool
+	}{
+		{name: "apagado ordenado", addr: "127.0.0.1:0"},
+		// Si no puede escuchar, los hooks se ejecutan igual
+		{name: "dirección ocupada", addr: busy.Addr().String(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			var calls []string
+			app := New(WithAddr(tt.addr))
+			recordHooks(app, &calls, nil, "primero", "segundo")
+
+			err := app.Run(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() = %v, se esperaba error: %t", err, tt.wantErr)
+			}
+			if got := strings.Join(calls, " > "); got != "segundo > primero" {
+				t.Errorf("orden de los hooks = %s, se esperaba segundo > primero", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Largos máximos de los campos de perfil, iguales a los de las columnas
+const (
+	maxNameLength      = 100
+	maxBioLength       = 500
+	maxAvatarURLLength = 255
+)
+
+// ProfileUpdate son los cambios pedidos al perfil; un campo nil no se toca
+type ProfileUpdate struct {
+	Name      *string
+	Bio       *string
+	AvatarURL *string
+}
+
+// UpdateProfile modifica el nombre y los datos de perfil del usuario
+func (s *UserService) UpdateProfile(ctx context.Context, userID uint, update ProfileUpdate) (*models.User, error) {
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields apperrors.FieldErrors
+	if update.Name != nil {
+		user.Name = strings.TrimSpace(*update.Name)
+		if problem := validateName(user.Name); problem != "" {
+			fields.Add("name", problem)
+		}
+	}
+
+	if update.Bio != nil {
+		user.Bio = strings.TrimSpace(*update.Bio)
+		if utf8.RuneCountInString(user.Bio) > maxBioLength {
+			fields.Add("bio", fmt.Sprintf("la biografía no puede superar los %d caracteres", maxBioLength))
+		}
+	}
+
+	if update.AvatarURL != nil {
+		user.AvatarURL = strings.TrimSpace(*update.AvatarURL)
+		// Una cadena vacía quita el avatar
+		if user.AvatarURL != "" && !validAvatarURL(user.AvatarURL) {
+			fields.Add("avatar_url", fmt.Sprintf("debe ser una URL http o https de hasta %d caracteres", maxAvatarURLLength))
+		}
+	}
+
+	if err := fields.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateProfile(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ChangePassword reemplaza la contraseña tras comprobar la actual. Las
+// demás sesiones se cierran y quien hizo el cambio recibe tokens nuevos.
+func (s *UserService) ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword string) (*TokenPair, error) {
+	user, err := s.checkPassword(ctx, userID, "current_password", currentPassword)
+	if err != nil {
+		return nil, err
+	}
+	if problem := s.passwords.validate(newPassword); problem != "" {
+		return nil, apperrors.InvalidField("new_password", problem)
+	}
+	if currentPassword == newPassword {
+		return nil, apperrors.InvalidField("new_password", "la nueva contraseña debe ser distinta de la actual")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("error al encriptar contraseña: %w", err)
+	}
+	if err := s.repo.UpdatePassword(ctx, user.ID, string(hashedPassword)); err != nil {
+		return nil, err
+	}
+
+	return s.tokens.RestartSessions(ctx, user)
+}
+
+// checkPassword confirma la contraseña del usuario autenticado antes de un
+// cambio sensible. Una contraseña incorrecta es un error de validación del
+// campo indicado y no un 401, que el cliente tomaría como una sesión vencida.
+func (s *UserService) checkPassword(ctx context.Context, userID uint, field, password string) (*models.User, error) {
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// FindByID no lee el hash de la contraseña
+	withPassword, err := s.repo.FindByEmail(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(withPassword.Password), []byte(password)); err != nil {
+		return nil, apperrors.InvalidField(field, "la contraseña actual es incorrecta")
+	}
+	return user, nil
+}
+
+// validateName devuelve por qué el nombre no es válido, o "" si lo es
+func validateName(name string) string {
+	if name == "" {
+		return "el nombre es requerido"
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return fmt.Sprintf("el nombre no puede superar los %d caracteres", maxNameLength)
+	}
+	return ""
+}
+
+func validAvatarURL(raw string) bool {
+	if len(raw) > maxAvatarURLLength {
+		return false
+	}
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
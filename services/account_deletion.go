@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gopost-api/database"
+)
+
+// DeleteAccount programa el borrado de la cuenta tras confirmar la
+// contraseña y cierra todas sus sesiones. Devuelve cuándo se purgará, o
+// nil si no hay período de gracia y ya se borró.
+func (s *UserService) DeleteAccount(ctx context.Context, userID uint, password string) (*time.Time, error) {
+	if _, err := s.checkPassword(ctx, userID, "password", password); err != nil {
+		return nil, err
+	}
+
+	now := database.Now()
+	if err := s.repo.ScheduleDeletion(ctx, userID, now); err != nil {
+		return nil, err
+	}
+	if err := s.tokens.LogoutAll(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	grace := s.settings.DeletionGrace
+	if grace <= 0 {
+		if _, err := s.accounts.PurgeUser(ctx, userID, now, s.settings.DeletionMode); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	purgeAt := now.Add(grace)
+	return &purgeAt, nil
+}
+
+// PurgeDeletedAccounts borra las cuentas cuyo período de gracia venció y
+// devuelve cuántas se borraron. Un error en una cuenta no detiene al resto.
+func (s *UserService) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	before := database.Now().Add(-s.settings.DeletionGrace)
+	ids, err := s.repo.PendingDeletions(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		ok, err := s.accounts.PurgeUser(ctx, id, before, s.settings.DeletionMode)
+		if err != nil {
+			log.Printf("Error al eliminar la cuenta %d: %v", id, err)
+			continue
+		}
+		if ok {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// RunAccountPurger purga cada interval las cuentas vencidas y las
+// revocaciones de tokens ya expirados, hasta que se cancele ctx
+func (s *UserService) RunAccountPurger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeDeletedAccounts(ctx)
+		if err != nil {
+			log.Printf("Error al purgar cuentas eliminadas: %v", err)
+		} else if purged > 0 {
+			log.Printf("✓ Cuentas eliminadas: %d", purged)
+		}
+
+		revocations, err := s.tokens.PurgeExpiredRevocations(ctx)
+		if err != nil {
+			log.Printf("Error al purgar revocaciones expiradas: %v", err)
+		} else if revocations > 0 {
+			log.Printf("✓ Revocaciones expiradas eliminadas: %d", revocations)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
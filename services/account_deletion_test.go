@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/repositories"
+)
+
+// signUpTestUser registra un usuario con contraseña, para las operaciones
+// que la piden
+func signUpTestUser(t *testing.T, ts *testServices, email string) *models.User {
+	t.Helper()
+	user, err := ts.userService.SignUp(context.Background(), "Ana", email, "Secreta-123")
+	if err != nil {
+		t.Fatalf("SignUp: %v", err)
+	}
+	return user
+}
+
+func TestDeleteAccountGracePeriod(t *testing.T) {
+	tests := []struct {
+		name      string
+		grace     time.Duration
+		wantPurge bool
+	}{
+		{name: "sin período de gracia", wantPurge: true},
+		{name: "con período de gracia", grace: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ts := newTestServices(t)
+			ts.userService.settings.DeletionGrace = tt.grace
+			user := signUpTestUser(t, ts, "ana@example.com")
+
+			start := database.Now()
+			purgeAt, err := ts.userService.DeleteAccount(ctx, user.ID, "Secreta-123")
+			if err != nil {
+				t.Fatalf("DeleteAccount: %v", err)
+			}
+
+			_, err = ts.users.FindByID(ctx, user.ID)
+			if tt.wantPurge {
+				if purgeAt != nil {
+					t.Errorf("DeleteAccount() = %v, se esperaba nil", purgeAt)
+				}
+				if !errors.Is(err, apperrors.ErrNotFound) {
+					t.Errorf("FindByID() error = %v, se esperaba ErrNotFound", err)
+				}
+				return
+			}
+			if purgeAt == nil || purgeAt.Before(start.Add(tt.grace)) {
+				t.Errorf("DeleteAccount() = %v, se esperaba %v o después", purgeAt, start.Add(tt.grace))
+			}
+			if err != nil {
+				t.Errorf("la cuenta se borró durante el período de gracia: %v", err)
+			}
+		})
+	}
+}
+
+// Los access tokens de una cuenta borrada deben seguir revocados: en SQL
+// el corte de revocación no puede borrarse en cascada con el usuario
+func TestDeleteAccountKeepsAccessTokensRevoked(t *testing.T) {
+	tests := []struct {
+		name    string
+		service func(t *testing.T) *UserService
+	}{
+		{name: "memoria", service: func(t *testing.T) *UserService { return newTestServices(t).userService }},
+		{name: "sqlite", service: newSQLUserService},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			userService := tt.service(t)
+			userService.settings.DeletionGrace = 0
+			user, err := userService.SignUp(ctx, "Ana", "ana@example.com", "Secreta-123")
+			if err != nil {
+				t.Fatalf("SignUp: %v", err)
+			}
+			pair, err := userService.Login(ctx, "ana@example.com", "Secreta-123")
+			if err != nil {
+				t.Fatalf("Login: %v", err)
+			}
+
+			if _, err := userService.DeleteAccount(ctx, user.ID, "Secreta-123"); err != nil {
+				t.Fatalf("DeleteAccount: %v", err)
+			}
+			if _, err := userService.repo.FindByID(ctx, user.ID); !errors.Is(err, apperrors.ErrNotFound) {
+				t.Fatalf("FindByID() error = %v, se esperaba ErrNotFound", err)
+			}
+
+			if _, err := userService.tokens.Verify(ctx, pair.AccessToken); !errors.Is(err, apperrors.ErrUnauthorized) {
+				t.Errorf("Verify() error = %v, se esperaba ErrUnauthorized", err)
+			}
+			if _, err := userService.Refresh(ctx, pair.RefreshToken); !errors.Is(err, apperrors.ErrUnauthorized) {
+				t.Errorf("Refresh() error = %v, se esperaba ErrUnauthorized", err)
+			}
+		})
+	}
+}
+
+func TestPurgeDeletedAccountsWaitsForGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	ts.userService.settings.DeletionGrace = time.Hour
+	expired := createTestUser(t, ts, "vencida@example.com")
+	pending := createTestUser(t, ts, "pendiente@example.com")
+	kept := createTestUser(t, ts, "activa@example.com")
+
+	now := database.Now()
+	if err := ts.users.ScheduleDeletion(ctx, expired.ID, now.Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.users.ScheduleDeletion(ctx, pending.ID, now.Add(-30*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	purged, err := ts.userService.PurgeDeletedAccounts(ctx)
+	if err != nil {
+		t.Fatalf("PurgeDeletedAccounts: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("PurgeDeletedAccounts() = %d, se esperaba 1", purged)
+	}
+	if _, err := ts.users.FindByID(ctx, expired.ID); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("la cuenta vencida no se borró: %v", err)
+	}
+	for _, user := range []*models.User{pending, kept} {
+		if _, err := ts.users.FindByID(ctx, user.ID); err != nil {
+			t.Errorf("la cuenta %s se borró: %v", user.Email, err)
+		}
+	}
+}
+
+func TestLoginCancelsAccountDeletion(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	ts.userService.settings.DeletionGrace = time.Hour
+	user := signUpTestUser(t, ts, "ana@example.com")
+
+	if _, err := ts.userService.DeleteAccount(ctx, user.ID, "Secreta-123"); err != nil {
+		t.Fatalf("DeleteAccount: %v", err)
+	}
+	if _, err := ts.userService.Login(ctx, "ana@example.com", "Secreta-123"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	got, err := ts.users.FindByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if got.DeletionRequestedAt != nil {
+		t.Errorf("DeletionRequestedAt = %v, se esperaba nil", got.DeletionRequestedAt)
+	}
+}
+
+func TestPurgeDeletedAccountsPostsMode(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         repositories.PostsOnDelete
+		wantPostKept bool
+	}{
+		{name: "anonymize", mode: repositories.PostsAnonymize, wantPostKept: true},
+		{name: "delete", mode: repositories.PostsDelete},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ts := newTestServices(t)
+			ts.userService.settings.DeletionGrace = time.Hour
+			ts.userService.settings.DeletionMode = tt.mode
+			user := createTestUser(t, ts, "ana@example.com")
+			post := &models.Post{UserID: user.ID, Title: "Hola", Content: "Mi primer post"}
+			if err := ts.posts.Create(ctx, post); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+
+			if err := ts.users.ScheduleDeletion(ctx, user.ID, database.Now().Add(-2*time.Hour)); err != nil {
+				t.Fatal(err)
+			}
+			if purged, err := ts.userService.PurgeDeletedAccounts(ctx); err != nil || purged != 1 {
+				t.Fatalf("PurgeDeletedAccounts() = %d, %v; se esperaba 1", purged, err)
+			}
+
+			got, err := ts.posts.FindByID(ctx, post.ID)
+			if !tt.wantPostKept {
+				if !errors.Is(err, apperrors.ErrNotFound) {
+					t.Errorf("FindByID() error = %v, se esperaba ErrNotFound", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("el post se borró: %v", err)
+			}
+			deleted, err := ts.users.FindByEmail(ctx, models.DeletedUserEmail)
+			if err != nil {
+				t.Fatalf("no existe la cuenta %s: %v", models.DeletedUserEmail, err)
+			}
+			if got.UserID != deleted.ID {
+				t.Errorf("UserID = %d, se esperaba la cuenta eliminada %d", got.UserID, deleted.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/models"
+)
+
+// wantFieldError comprueba que err sea un error de validación del campo
+func wantFieldError(t *testing.T, err error, field string) {
+	t.Helper()
+	var appErr *apperrors.Error
+	if !errors.Is(err, apperrors.ErrValidation) || !errors.As(err, &appErr) || len(appErr.Fields) == 0 || appErr.Fields[0].Field != field {
+		t.Errorf("error = %v, se esperaba un error de validación en el campo %q", err, field)
+	}
+}
+
+func TestChangePasswordRejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   string
+		next      string
+		wantField string
+	}{
+		{name: "contraseña actual incorrecta", current: "otra-cosa-123", next: "Nueva-Clave-456", wantField: "current_password"},
+		{name: "nueva demasiado corta", current: "Secreta-123", next: "corta", wantField: "new_password"},
+		{name: "nueva común", current: "Secreta-123", next: "password", wantField: "new_password"},
+		{name: "nueva igual a la actual", current: "Secreta-123", next: "Secreta-123", wantField: "new_password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ts := newTestServices(t)
+			user, err := ts.userService.SignUp(ctx, "Ana", "ana@example.com", "Secreta-123")
+			if err != nil {
+				t.Fatalf("SignUp: %v", err)
+			}
+			session, err := ts.userService.Login(ctx, "ana@example.com", "Secreta-123")
+			if err != nil {
+				t.Fatalf("Login: %v", err)
+			}
+
+			_, err = ts.userService.ChangePassword(ctx, user.ID, tt.current, tt.next)
+			wantFieldError(t, err, tt.wantField)
+
+			// Un cambio rechazado no toca la contraseña ni las sesiones
+			if _, err := ts.userService.Login(ctx, "ana@example.com", "Secreta-123"); err != nil {
+				t.Errorf("la contraseña anterior dejó de servir: %v", err)
+			}
+			if _, err := ts.tokens.Verify(ctx, session.AccessToken); err != nil {
+				t.Errorf("un cambio rechazado cerró la sesión: %v", err)
+			}
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	user, err := ts.userService.SignUp(ctx, "Ana", "ana@example.com", "Secreta-123")
+	if err != nil {
+		t.Fatalf("SignUp: %v", err)
+	}
+	current, err := ts.userService.Login(ctx, "ana@example.com", "Secreta-123")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	other, err := ts.userService.Login(ctx, "ana@example.com", "Secreta-123")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	pair, err := ts.userService.ChangePassword(ctx, user.ID, "Secreta-123", "Nueva-Clave-456")
+	if err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+
+	// Se cierran todas las sesiones, también la de quien hizo el cambio
+	for name, old := range map[string]*TokenPair{"actual": current, "otra": other} {
+		if _, err := ts.tokens.Verify(ctx, old.AccessToken); !errors.Is(err, apperrors.ErrUnauthorized) {
+			t.Errorf("el access token de la sesión %s sigue vigente: %v", name, err)
+		}
+		if _, err := ts.userService.Refresh(ctx, old.RefreshToken); !errors.Is(err, apperrors.ErrUnauthorized) {
+			t.Errorf("el refresh token de la sesión %s sigue vigente: %v", name, err)
+		}
+	}
+
+	// El par nuevo funciona y se emite justo después del corte
+	claims, err := ts.tokens.Verify(ctx, pair.AccessToken)
+	if err != nil {
+		t.Fatalf("el access token nuevo quedó revocado: %v", err)
+	}
+	cutoff, err := ts.revocations.UserTokensRevokedBefore(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("UserTokensRevokedBefore: %v", err)
+	}
+	if want := cutoff.Add(time.Millisecond); !claims.IssuedAt.Equal(want) {
+		t.Errorf("iat = %v, se esperaba %v", claims.IssuedAt, want)
+	}
+	if _, err := ts.userService.Refresh(ctx, pair.RefreshToken); err != nil {
+		t.Errorf("el refresh token nuevo no sirve: %v", err)
+	}
+
+	if _, err := ts.userService.Login(ctx, "ana@example.com", "Secreta-123"); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("la contraseña anterior sigue sirviendo: %v", err)
+	}
+	if _, err := ts.userService.Login(ctx, "ana@example.com", "Nueva-Clave-456"); err != nil {
+		t.Errorf("la contraseña nueva no sirve: %v", err)
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	original := models.User{Name: "Ana", Bio: "Escribo sobre Go", AvatarURL: "https://example.com/ana.png"}
+
+	tests := []struct {
+		name      string
+		update    ProfileUpdate
+		want      models.User
+		wantField string
+	}{
+		{name: "sin cambios", update: ProfileUpdate{}, want: original},
+		{name: "solo el nombre", update: ProfileUpdate{Name: ptr("  Ana María  ")},
+			want: models.User{Name: "Ana María", Bio: original.Bio, AvatarURL: original.AvatarURL}},
+		{name: "solo la biografía", update: ProfileUpdate{Bio: ptr("Ahora escribo sobre Rust")},
+			want: models.User{Name: original.Name, Bio: "Ahora escribo sobre Rust", AvatarURL: original.AvatarURL}},
+		{name: "quitar el avatar", update: ProfileUpdate{AvatarURL: ptr("")},
+			want: models.User{Name: original.Name, Bio: original.Bio}},
+		{name: "nombre vacío", update: ProfileUpdate{Name: ptr("   ")}, wantField: "name"},
+		{name: "biografía demasiado larga", update: ProfileUpdate{Bio: ptr(strings.Repeat("ñ", maxBioLength+1))}, wantField: "bio"},
+		{name: "avatar sin http", update: ProfileUpdate{AvatarURL: ptr("ftp://example.com/ana.png")}, wantField: "avatar_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ts := newTestServices(t)
+			user := &models.User{Name: original.Name, Email: "ana@example.com", Password: "x", Bio: original.Bio, AvatarURL: original.AvatarURL}
+			if err := ts.users.Create(ctx, user); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if err := ts.users.UpdateProfile(ctx, user); err != nil {
+				t.Fatalf("UpdateProfile: %v", err)
+			}
+
+			_, err := ts.userService.UpdateProfile(ctx, user.ID, tt.update)
+			if tt.wantField != "" {
+				wantFieldError(t, err, tt.wantField)
+			} else if err != nil {
+				t.Fatalf("UpdateProfile: %v", err)
+			}
+
+			// Lo guardado es lo esperado; un cambio rechazado no guarda nada
+			want := tt.want
+			if tt.wantField != "" {
+				want = original
+			}
+			stored, err := ts.users.FindByID(ctx, user.ID)
+			if err != nil {
+				t.Fatalf("FindByID: %v", err)
+			}
+			if stored.Name != want.Name || stored.Bio != want.Bio || stored.AvatarURL != want.AvatarURL {
+				t.Errorf("perfil = {%q %q %q}, se esperaba {%q %q %q}",
+					stored.Name, stored.Bio, stored.AvatarURL, want.Name, want.Bio, want.AvatarURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	_ "embed"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gopost-api/config"
+)
+
+const (
+	// bcrypt ignora lo que sigue a los primeros 72 bytes: una contraseña
+	// más larga daría una falsa sensación de seguridad
+	maxPasswordBytes = 72
+	// Límites de RFC 5321 para una dirección y su parte local
+	maxEmailLength      = 254
+	maxEmailLocalLength = 64
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+// commonPasswords son las contraseñas de la lista incluida, en minúsculas
+var commonPasswords = parseCommonPasswords(commonPasswordsFile)
+
+func parseCommonPasswords(file string) map[string]struct{} {
+	passwords := make(map[string]struct{})
+	for _, line := range strings.Split(file, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = struct{}{}
+	}
+	return passwords
+}
+
+// normalizeEmail expresa el email como se guarda: sin espacios alrededor y
+// en minúsculas
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// validateEmail normaliza el email y comprueba que sea una dirección simple
+// según RFC 5322: sin nombre visible, comentarios ni partes entre comillas.
+// Devuelve el email normalizado y el motivo por el que no es válido, o ""
+// si lo es.
+func validateEmail(email string) (string, string) {
+	email = normalizeEmail(email)
+	if email == "" {
+		return email, "el email es requerido"
+	}
+	if len(email) > maxEmailLength {
+		return email, fmt.Sprintf("el email no puede superar los %d caracteres", maxEmailLength)
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || addr.Name != "" {
+		return email, "el email no es válido"
+	}
+
+	at := strings.LastIndex(email, "@")
+	local, domain := email[:at], email[at+1:]
+	if len(local) > maxEmailLocalLength || !validEmailDomain(domain) {
+		return email, "el email no es válido"
+	}
+	return email, ""
+}
+
+// validEmailDomain exige un dominio con al menos dos etiquetas no vacías,
+// sin guiones en los extremos, como los que se resuelven en Internet
+func validEmailDomain(domain string) bool {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+	}
+	return true
+}
+
+// PasswordPolicy son los requisitos de las contraseñas nuevas. El largo
+// se cuenta en caracteres; el máximo es siempre maxPasswordBytes.
+type PasswordPolicy struct {
+	MinLength     int
+	RequireLower  bool
+	RequireUpper  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// RejectCommon rechaza las contraseñas de common_passwords.txt
+	RejectCommon bool
+}
+
+// NewPasswordPolicy arma la política a partir de la configuración
+func NewPasswordPolicy(cfg *config.Config) PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:     cfg.PasswordMinLength,
+		RequireLower:  cfg.PasswordRequireLower,
+		RequireUpper:  cfg.PasswordRequireUpper,
+		RequireDigit:  cfg.PasswordRequireDigit,
+		RequireSymbol: cfg.PasswordRequireSymbol,
+		RejectCommon:  cfg.PasswordRejectCommon,
+	}
+}
+
+// validate comprueba la contraseña contra la política y devuelve el motivo
+// por el que no la cumple, o "" si la cumple
+func (p PasswordPolicy) validate(password string) string {
+	if password == "" {
+		return "la contraseña es requerida"
+	}
+	if len(password) > maxPasswordBytes {
+		return fmt.Sprintf("la contraseña no puede superar los %d bytes", maxPasswordBytes)
+	}
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return fmt.Sprintf("la contraseña debe tener al menos %d caracteres", p.MinLength)
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r):
+			symbol = true
+		}
+	}
+	switch {
+	case p.RequireLower && !lower:
+		return "la contraseña debe incluir al menos una minúscula"
+	case p.RequireUpper && !upper:
+		return "la contraseña debe incluir al menos una mayúscula"
+	case p.RequireDigit && !digit:
+		return "la contraseña debe incluir al menos un dígito"
+	case p.RequireSymbol && !symbol:
+		return "la contraseña debe incluir al menos un símbolo"
+	}
+
+	if p.RejectCommon {
+		if _, common := commonPasswords[strings.ToLower(password)]; common {
+			return "la contraseña es demasiado común, elige otra"
+		}
+	}
+	return ""
+}
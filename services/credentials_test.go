@@ -0,0 +1,40 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	strict := PasswordPolicy{MinLength: 10, RequireLower: true, RequireUpper: true, RequireDigit: true, RequireSymbol: true, RejectCommon: true}
+
+	tests := []struct {
+		name     string
+		policy   PasswordPolicy
+		password string
+		wantOK   bool
+	}{
+		{name: "vacía", policy: PasswordPolicy{}, password: ""},
+		{name: "más de 72 bytes", policy: PasswordPolicy{}, password: strings.Repeat("a", maxPasswordBytes+1)},
+		{name: "corta", policy: PasswordPolicy{MinLength: 8}, password: "abc12"},
+		{name: "largo en caracteres, no en bytes", policy: PasswordPolicy{MinLength: 8}, password: "ñandúñandú", wantOK: true},
+		{name: "común", policy: PasswordPolicy{MinLength: 8, RejectCommon: true}, password: "Password"},
+		{name: "común permitida", policy: PasswordPolicy{MinLength: 8}, password: "password", wantOK: true},
+		{name: "falta mayúscula", policy: strict, password: "correcto-caballo-9"},
+		{name: "falta dígito", policy: strict, password: "Correcto-Caballo"},
+		{name: "falta símbolo", policy: strict, password: "CorrectoCaballo9"},
+		{name: "cumple todo", policy: strict, password: "Correcto-Caballo-9", wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := tt.policy.validate(tt.password)
+			if tt.wantOK && problem != "" {
+				t.Errorf("validate(%q) = %q, se esperaba válida", tt.password, problem)
+			}
+			if !tt.wantOK && problem == "" {
+				t.Errorf("validate(%q) la aceptó, se esperaba un rechazo", tt.password)
+			}
+		})
+	}
+}
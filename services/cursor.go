@@ -0,0 +1,55 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/repositories"
+)
+
+// cursorPurpose separa las firmas de los cursores de las de otros usos
+const cursorPurpose = "cursor"
+
+// cursorPayload es el contenido de un cursor: la posición del último post
+// entregado en el orden created_at DESC, id DESC
+type cursorPayload struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uint      `json:"id"`
+}
+
+// encodeCursor genera un cursor opaco "payload.firma", ambos en base64 URL.
+// La firma HMAC impide que el cliente fabrique o altere posiciones.
+func encodeCursor(signer *Signer, key repositories.PostKey) (string, error) {
+	payload, err := json.Marshal(cursorPayload{CreatedAt: key.CreatedAt.UTC(), ID: key.ID})
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signer.sign(cursorPurpose, encoded), nil
+}
+
+// decodeCursor verifica la firma y devuelve la posición codificada
+func decodeCursor(signer *Signer, cursor string) (*repositories.PostKey, error) {
+	invalid := apperrors.Validation("cursor inválido")
+
+	encoded, signature, ok := strings.Cut(cursor, ".")
+	if !ok || !signer.verify(cursorPurpose, encoded, signature) {
+		return nil, invalid
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil || payload.ID == 0 {
+		return nil, invalid
+	}
+
+	return &repositories.PostKey{CreatedAt: payload.CreatedAt, ID: payload.ID}, nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/repositories"
+)
+
+func newTestSigner(t *testing.T, secret string) *Signer {
+	t.Helper()
+	signer, err := NewSigner("TEST_SECRET", secret)
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+	return signer
+}
+
+func TestNewSigner(t *testing.T) {
+	jwtSecret := strings.Repeat("j", minSecretLength)
+
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{name: "vacío", secret: "", wantErr: true},
+		{name: "corto", secret: "corto", wantErr: true},
+		{name: "igual a JWT_SECRET", secret: jwtSecret, wantErr: true},
+		{name: "propio", secret: strings.Repeat("c", minSecretLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSigner("CURSOR_SECRET", tt.secret, jwtSecret)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewSigner(%q) error = %v, se esperaba error = %t", tt.secret, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	signer := newTestSigner(t, strings.Repeat("a", minSecretLength))
+	key := repositories.PostKey{CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), ID: 42}
+
+	cursor, err := encodeCursor(signer, key)
+	if err != nil {
+		t.Fatalf("encodeCursor: %v", err)
+	}
+	got, err := decodeCursor(signer, cursor)
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	if got.ID != key.ID || !got.CreatedAt.Equal(key.CreatedAt) {
+		t.Errorf("decodeCursor() = %+v, se esperaba %+v", got, key)
+	}
+}
+
+func TestDecodeCursorRejectsTampering(t *testing.T) {
+	signer := newTestSigner(t, strings.Repeat("a", minSecretLength))
+	other := newTestSigner(t, strings.Repeat("b", minSecretLength))
+
+	key := repositories.PostKey{CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), ID: 42}
+	cursor, err := encodeCursor(signer, key)
+	if err != nil {
+		t.Fatalf("encodeCursor: %v", err)
+	}
+	encoded, signature, _ := strings.Cut(cursor, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"t":"2024-05-01T10:00:00Z","id":1}`))
+	otherCursor, _ := encodeCursor(other, key)
+	zeroID := base64.RawURLEncoding.EncodeToString([]byte(`{"t":"2024-05-01T10:00:00Z","id":0}`))
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "sin firma", cursor: encoded},
+		{name: "firma vacía", cursor: encoded + "."},
+		{name: "payload alterado", cursor: forged + "." + signature},
+		{name: "firma alterada", cursor: encoded + "." + strings.ToUpper(signature)},
+		{name: "firmado con otro secreto", cursor: otherCursor},
+		{name: "firma de otro propósito", cursor: encoded + "." + signer.sign(models.TokenPurposeEmailVerification, encoded)},
+		{name: "payload no es base64", cursor: "%%%." + signer.sign(cursorPurpose, "%%%")},
+		{name: "payload no es JSON", cursor: "bm8." + signer.sign(cursorPurpose, "bm8")},
+		{name: "id cero", cursor: zeroID + "." + signer.sign(cursorPurpose, zeroID)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeCursor(signer, tt.cursor)
+			if !errors.Is(err, apperrors.ErrValidation) {
+				t.Errorf("decodeCursor() error = %v, se esperaba un error de validación", err)
+			}
+		})
+	}
+}
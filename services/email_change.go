@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/mailer"
+	"github.com/gopost-api/models"
+)
+
+// RequestEmailChange envía un token a la nueva dirección. El email de la
+// cuenta no cambia hasta que se confirma con ConfirmEmailChange.
+func (s *UserService) RequestEmailChange(ctx context.Context, userID uint, newEmail, password string) error {
+	newEmail, problem := validateEmail(newEmail)
+	if problem != "" {
+		return apperrors.InvalidField("email", problem)
+	}
+
+	user, err := s.checkPassword(ctx, userID, "password", password)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return apperrors.InvalidField("email", "el nuevo email es igual al actual")
+	}
+
+	exists, err := s.repo.EmailExists(ctx, newEmail)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return apperrors.Conflict("el email ya está registrado")
+	}
+
+	ttl := s.settings.EmailVerificationTTL
+	token, err := s.userTokens.issue(ctx, user.ID, models.TokenPurposeEmailChange, newEmail, ttl)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(`Hola %s:
+
+Pediste usar esta dirección en tu cuenta de GoPost. Confírmala abriendo
+este enlace:
+
+%s
+
+O envía este token a POST /auth/email/confirm:
+
+%s
+
+El enlace vence en %s. Si no lo pediste, ignora este correo.
+`, user.Name, s.appLink("/confirm-email", token), token, ttl)
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      newEmail,
+		Subject: "Confirma tu nuevo email en GoPost",
+		Body:    body,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Aviso a la dirección actual por si el pedido no fue del titular
+	notice := fmt.Sprintf(`Hola %s:
+
+Se pidió cambiar el email de tu cuenta de GoPost a %s. El cambio solo se
+aplica cuando se confirma desde esa dirección.
+
+Si no fuiste tú, cambia tu contraseña cuanto antes.
+`, user.Name, newEmail)
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Pedido de cambio de email en GoPost",
+		Body:    notice,
+	})
+	if err != nil {
+		log.Printf("Error al avisar del cambio de email a %s: %v", user.Email, err)
+	}
+	return nil
+}
+
+// ConfirmEmailChange canjea el token enviado a la nueva dirección y la
+// guarda como email verificado de la cuenta
+func (s *UserService) ConfirmEmailChange(ctx context.Context, token string) (*models.User, error) {
+	stored, err := s.userTokens.consume(ctx, token, models.TokenPurposeEmailChange)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateEmail(ctx, stored.UserID, stored.Email); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, stored.UserID)
+}
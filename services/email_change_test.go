@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmailChangeUsesSettings(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	ts.userService.settings.AppURL = "https://gopost.example"
+	ts.userService.settings.EmailVerificationTTL = 2 * time.Hour
+
+	user, err := ts.userService.SignUp(ctx, "Ana", "ana@example.com", "Secreta-123")
+	if err != nil {
+		t.Fatalf("SignUp: %v", err)
+	}
+	if err := ts.userService.RequestEmailChange(ctx, user.ID, "nuevo@example.com", "Secreta-123"); err != nil {
+		t.Fatalf("RequestEmailChange: %v", err)
+	}
+
+	// Se envían la verificación del registro, la confirmación a la nueva
+	// dirección y el aviso a la actual
+	if len(ts.mailer.sent) != 3 {
+		t.Fatalf("se enviaron %d correos, se esperaban 3", len(ts.mailer.sent))
+	}
+	msg := ts.mailer.sent[1]
+	if msg.To != "nuevo@example.com" {
+		t.Errorf("la confirmación se envió a %s, se esperaba la nueva dirección", msg.To)
+	}
+	for _, want := range []string{"https://gopost.example/confirm-email?token=", "vence en 2h0m0s"} {
+		if !strings.Contains(msg.Body, want) {
+			t.Errorf("el correo no contiene %q:\n%s", want, msg.Body)
+		}
+	}
+}
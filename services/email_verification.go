@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/mailer"
+	"github.com/gopost-api/models"
+)
+
+// resendInterval es el tiempo mínimo entre dos correos del mismo tipo para
+// un usuario
+const resendInterval = time.Minute
+
+// VerifyEmail canjea un token de verificación y marca el email como
+// verificado
+func (s *UserService) VerifyEmail(ctx context.Context, token string) (*models.User, error) {
+	stored, err := s.userTokens.consume(ctx, token, models.TokenPurposeEmailVerification)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// El token verifica la dirección a la que se envió, no la actual
+	if !strings.EqualFold(user.Email, stored.Email) {
+		return nil, apperrors.Validation("el token es inválido o expiró")
+	}
+
+	if !user.EmailVerified {
+		if err := s.repo.MarkEmailVerified(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		user.EmailVerified = true
+	}
+	return user, nil
+}
+
+// ResendVerification envía un nuevo token de verificación. No informa si
+// el email existe o ya estaba verificado, para no revelar qué cuentas hay:
+// el correo se envía en segundo plano y sus errores solo se registran en
+// el log, así la respuesta y su demora son las mismas en todos los casos.
+func (s *UserService) ResendVerification(ctx context.Context, email string) error {
+	user, err := s.repo.FindByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.EmailVerified {
+		return nil
+	}
+
+	s.sendDetached(ctx, "la verificación", user.Email, func(ctx context.Context) error {
+		recent, err := s.userTokens.recentlyIssued(ctx, user.ID, models.TokenPurposeEmailVerification, resendInterval)
+		if err != nil || recent {
+			return err
+		}
+		return s.sendVerification(ctx, user)
+	})
+	return nil
+}
+
+func (s *UserService) sendVerification(ctx context.Context, user *models.User) error {
+	token, err := s.userTokens.issue(ctx, user.ID, models.TokenPurposeEmailVerification, user.Email, s.settings.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(`Hola %s:
+
+Confirma tu email abriendo este enlace:
+
+%s
+
+O envía este token a POST /auth/verify:
+
+%s
+
+El enlace vence en %s. Si no creaste una cuenta en GoPost, ignora este correo.
+`, user.Name, s.appLink("/verify-email", token), token, s.settings.EmailVerificationTTL)
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Confirma tu email en GoPost",
+		Body:    body,
+	})
+}
+
+// appLink arma un enlace de la aplicación con el token como parámetro
+func (s *UserService) appLink(path, token string) string {
+	return strings.TrimSuffix(s.settings.AppURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopost-api/models"
+)
+
+func TestResendVerificationHidesMailerErrors(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	if err := ts.users.Create(ctx, &models.User{Name: "Ana", Email: "ana@example.com", Password: "x"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	ts.mailer.fail = true
+
+	// Con o sin cuenta la respuesta es la misma, aunque falle el correo
+	for _, email := range []string{"ana@example.com", "nadie@example.com"} {
+		if err := ts.userService.ResendVerification(ctx, email); err != nil {
+			t.Errorf("ResendVerification(%q) = %v, se esperaba nil", email, err)
+		}
+	}
+}
+
+// El correo se envía fuera de la petición: un servidor de correo lento no
+// demora la respuesta ni falla porque la petición terminó
+func TestResendVerificationSendsDetachedFromRequest(t *testing.T) {
+	ts := newTestServices(t)
+	if err := ts.users.Create(context.Background(), &models.User{Name: "Ana", Email: "ana@example.com", Password: "x"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	ts.mailer.release = make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	returned := make(chan error, 1)
+	go func() { returned <- ts.userService.ResendVerification(ctx, "ana@example.com") }()
+
+	select {
+	case err := <-returned:
+		if err != nil {
+			t.Fatalf("ResendVerification: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ResendVerification esperó al envío del correo")
+	}
+	cancel()
+	close(ts.mailer.release)
+
+	if sent := ts.sentMail(t); len(sent) != 1 {
+		t.Errorf("se enviaron %d correos, se esperaba 1", len(sent))
+	}
+}
+
+func TestResendVerificationThrottles(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	if err := ts.users.Create(ctx, &models.User{Name: "Ana", Email: "ana@example.com", Password: "x"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := ts.userService.ResendVerification(ctx, "ANA@example.com"); err != nil {
+			t.Fatalf("ResendVerification: %v", err)
+		}
+		// El segundo pedido debe ver el token que emitió el primero
+		if err := ts.userService.Wait(ctx); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	if sent := ts.sentMail(t); len(sent) != 1 {
+		t.Errorf("se enviaron %d correos, se esperaba 1", len(sent))
+	}
+}
+
+func TestVerificationEmailUsesSettings(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	ts.userService.settings = UserSettings{AppURL: "https://gopost.example/", EmailVerificationTTL: 2 * time.Hour}
+
+	if _, err := ts.userService.SignUp(ctx, "Ana", "ana@example.com", "Secreta-123"); err != nil {
+		t.Fatalf("SignUp: %v", err)
+	}
+	if len(ts.mailer.sent) != 1 {
+		t.Fatalf("se enviaron %d correos, se esperaba 1", len(ts.mailer.sent))
+	}
+	body := ts.mailer.sent[0].Body
+	for _, want := range []string{"https://gopost.example/verify-email?token=", "vence en 2h0m0s"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("el correo no contiene %q:\n%s", want, body)
+		}
+	}
+}
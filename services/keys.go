@@ -0,0 +1,361 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gopost-api/config"
+)
+
+// JWK es una clave pública en formato JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC y OKP (Ed25519)
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKSet es el documento que publica /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// signingKey es una clave con su kid y el algoritmo con que se usa. Las
+// claves HMAC guardan el secreto en public y private.
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+}
+
+// KeySet firma los access tokens con la clave activa y los verifica con
+// cualquiera de las claves conocidas, elegida por el header kid. Durante
+// una rotación la clave anterior se configura solo para verificación
+// hasta que expiren los tokens que firmó.
+type KeySet struct {
+	signing *signingKey
+	keys    map[string]*signingKey
+}
+
+// LoadKeySet arma el KeySet según la configuración
+func LoadKeySet(cfg *config.Config) (*KeySet, error) {
+	signing, err := loadSigningKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &KeySet{signing: signing, keys: map[string]*signingKey{signing.id: signing}}
+	for _, entry := range cfg.JWTVerifyKeyFiles {
+		kid, path := "", entry
+		if i := strings.Index(entry, "="); i > 0 {
+			kid, path = entry[:i], entry[i+1:]
+		}
+
+		key, err := loadVerifyKey(path, kid)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := set.keys[key.id]; exists {
+			return nil, fmt.Errorf("el kid %q está repetido en las claves JWT", key.id)
+		}
+		set.keys[key.id] = key
+	}
+
+	return set, nil
+}
+
+func loadSigningKey(cfg *config.Config) (*signingKey, error) {
+	method := jwt.GetSigningMethod(cfg.JWTAlgorithm)
+	if method == nil {
+		return nil, fmt.Errorf("JWT_ALGORITHM %q no es válido", cfg.JWTAlgorithm)
+	}
+
+	if method == jwt.SigningMethodHS256 {
+		if len(cfg.JWTSecret) < minSecretLength {
+			return nil, fmt.Errorf("JWT_SECRET debe tener al menos %d caracteres", minSecretLength)
+		}
+		secret := []byte(cfg.JWTSecret)
+		kid := cfg.JWTKeyID
+		if kid == "" {
+			sum := sha256.Sum256(append([]byte("kid:"), secret...))
+			kid = base64.RawURLEncoding.EncodeToString(sum[:12])
+		}
+		return &signingKey{id: kid, method: method, private: secret, public: secret}, nil
+	}
+
+	if cfg.JWTPrivateKeyFile == "" {
+		return nil, fmt.Errorf("JWT_ALGORITHM %s requiere JWT_PRIVATE_KEY_FILE", cfg.JWTAlgorithm)
+	}
+	data, err := os.ReadFile(cfg.JWTPrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer la clave privada JWT: %w", err)
+	}
+	private, err := parsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("error en %s: %w", cfg.JWTPrivateKeyFile, err)
+	}
+
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("la clave de %s no sirve para firmar", cfg.JWTPrivateKeyFile)
+	}
+	keyMethod, err := methodForKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	if keyMethod != method {
+		return nil, fmt.Errorf("la clave de %s es para %s, no para %s", cfg.JWTPrivateKeyFile, keyMethod.Alg(), method.Alg())
+	}
+
+	key := &signingKey{id: cfg.JWTKeyID, method: method, private: private, public: signer.Public()}
+	if key.id == "" {
+		if key.id, err = thumbprint(key.public); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// loadVerifyKey carga una clave pública; el algoritmo se deduce del tipo
+// de clave y el kid, si no se indica, es su thumbprint
+func loadVerifyKey(path, kid string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer la clave JWT %s: %w", path, err)
+	}
+	public, err := parsePublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("error en %s: %w", path, err)
+	}
+	method, err := methodForKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("error en %s: %w", path, err)
+	}
+
+	if kid == "" {
+		if kid, err = thumbprint(public); err != nil {
+			return nil, err
+		}
+	}
+	return &signingKey{id: kid, method: method, public: public}, nil
+}
+
+// Sign firma los claims con la clave activa e incluye su kid en el header
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signing.method, claims)
+	token.Header["kid"] = k.signing.id
+	return token.SignedString(k.signing.private)
+}
+
+// Keyfunc elige la clave de verificación por kid. El algoritmo del token
+// debe ser el de esa clave, lo que impide que un token HS256 se verifique
+// usando una clave pública como secreto.
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := k.keys[kid]
+	if !ok && kid == "" && k.signing.method == jwt.SigningMethodHS256 {
+		// Tokens HS256 emitidos antes de que existiera el header kid
+		key, ok = k.signing, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("kid desconocido: %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("método de firma inesperado: %v", token.Header["alg"])
+	}
+	return key.public, nil
+}
+
+// Algorithms devuelve los algoritmos de todas las claves conocidas
+func (k *KeySet) Algorithms() []string {
+	seen := map[string]bool{}
+	var algorithms []string
+	for _, key := range k.keys {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			algorithms = append(algorithms, alg)
+		}
+	}
+	return algorithms
+}
+
+// JWKS devuelve las claves públicas asimétricas. Las claves HMAC son
+// secretas y nunca se publican.
+func (k *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	// La clave activa va primero
+	ids := []string{k.signing.id}
+	for id := range k.keys {
+		if id != k.signing.id {
+			ids = append(ids, id)
+		}
+	}
+
+	for _, id := range ids {
+		key := k.keys[id]
+		jwk, err := publicJWK(key.public)
+		if err != nil {
+			continue
+		}
+		jwk.Use = "sig"
+		jwk.Alg = key.method.Alg()
+		jwk.Kid = key.id
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// methodForKey deduce el algoritmo de firma a partir de la clave pública
+func methodForKey(public crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("las claves RSA deben tener al menos 2048 bits")
+		}
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("las claves EC deben usar la curva P-256")
+		}
+		return jwt.SigningMethodES256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("tipo de clave no soportado: %T", public)
+	}
+}
+
+// publicJWK convierte una clave pública a JWK, sin kid, use ni alg
+func publicJWK(public crypto.PublicKey) (JWK, error) {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		return JWK{Kty: "RSA", N: encode(key.N.Bytes()), E: encode(big.NewInt(int64(key.E)).Bytes())}, nil
+	case *ecdsa.PublicKey:
+		ecdhKey, err := key.ECDH()
+		if err != nil {
+			return JWK{}, err
+		}
+		// Formato sin comprimir: 0x04 || X || Y, cada coordenada de 32 bytes
+		point := ecdhKey.Bytes()[1:]
+		size := len(point) / 2
+		return JWK{Kty: "EC", Crv: "P-256", X: encode(point[:size]), Y: encode(point[size:])}, nil
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Crv: "Ed25519", X: encode(key)}, nil
+	default:
+		return JWK{}, fmt.Errorf("tipo de clave no soportado: %T", public)
+	}
+}
+
+// thumbprint calcula el JWK thumbprint de RFC 7638: el SHA-256 de los
+// miembros obligatorios de la clave en orden lexicográfico
+func thumbprint(public crypto.PublicKey) (string, error) {
+	jwk, err := publicJWK(public)
+	if err != nil {
+		return "", err
+	}
+
+	var members interface{}
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// parsePrivateKey acepta claves PKCS#8, PKCS#1 (RSA) y SEC 1 (EC)
+func parsePrivateKey(data []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no contiene un bloque PEM")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("bloque PEM %q no es una clave privada", block.Type)
+	}
+}
+
+// parsePublicKey acepta claves públicas PKIX o PKCS#1, certificados y
+// también claves privadas, de las que toma la parte pública
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no contiene un bloque PEM")
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	default:
+		private, err := parsePrivateKey(data)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := private.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("tipo de clave no soportado: %T", private)
+		}
+		return signer.Public(), nil
+	}
+}
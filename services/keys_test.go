@@ -0,0 +1,345 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gopost-api/config"
+)
+
+// writeKeyFiles guarda la clave privada (PKCS#8) y su pública (PKIX) en PEM
+// y devuelve las rutas de ambos archivos
+func writeKeyFiles(t *testing.T, name string, private crypto.Signer) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+
+	privatePath := filepath.Join(dir, name+".pem")
+	publicPath := filepath.Join(dir, name+".pub.pem")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return privatePath, publicPath
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	return key
+}
+
+func newECKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey: %v", err)
+	}
+	return key
+}
+
+func newEd25519Key(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey: %v", err)
+	}
+	return key
+}
+
+func testClaims() jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		Subject:   "1",
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+	}
+}
+
+// parseWithKeySet verifica el token como lo hace TokenService.Verify
+func parseWithKeySet(keys *KeySet, signed string) error {
+	_, err := jwt.ParseWithClaims(signed, &jwt.RegisteredClaims{}, keys.Keyfunc,
+		jwt.WithValidMethods(keys.Algorithms()), jwt.WithExpirationRequired(), jwt.WithIssuedAt(),
+	)
+	return err
+}
+
+func TestLoadKeySetRejectsWeakJWTSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{name: "sin configurar", secret: "", wantErr: true},
+		{name: "corto", secret: "token123", wantErr: true},
+		{name: "propio", secret: strings.Repeat("j", minSecretLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadKeySet(&config.Config{JWTAlgorithm: "HS256", JWTSecret: tt.secret})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadKeySet(%q) error = %v, se esperaba error = %t", tt.secret, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeySetSignAndVerify(t *testing.T) {
+	tests := []struct {
+		name string
+		alg  string
+		key  crypto.Signer
+	}{
+		{name: "RS256", alg: "RS256", key: newRSAKey(t)},
+		{name: "ES256", alg: "ES256", key: newECKey(t)},
+		{name: "EdDSA", alg: "EdDSA", key: newEd25519Key(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privatePath, _ := writeKeyFiles(t, "jwt", tt.key)
+			keys, err := LoadKeySet(&config.Config{JWTAlgorithm: tt.alg, JWTPrivateKeyFile: privatePath})
+			if err != nil {
+				t.Fatalf("LoadKeySet: %v", err)
+			}
+
+			signed, err := keys.Sign(testClaims())
+			if err != nil {
+				t.Fatalf("Sign: %v", err)
+			}
+			token, _, err := jwt.NewParser().ParseUnverified(signed, &jwt.RegisteredClaims{})
+			if err != nil {
+				t.Fatalf("ParseUnverified: %v", err)
+			}
+			if token.Header["alg"] != tt.alg {
+				t.Errorf("alg = %v, se esperaba %s", token.Header["alg"], tt.alg)
+			}
+			want, err := thumbprint(tt.key.Public())
+			if err != nil {
+				t.Fatalf("thumbprint: %v", err)
+			}
+			if token.Header["kid"] != want {
+				t.Errorf("kid = %v, se esperaba el thumbprint %s", token.Header["kid"], want)
+			}
+
+			if err := parseWithKeySet(keys, signed); err != nil {
+				t.Errorf("el token firmado no verifica: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadKeySetRejectsMismatchedAlgorithm(t *testing.T) {
+	privatePath, _ := writeKeyFiles(t, "jwt", newECKey(t))
+	if _, err := LoadKeySet(&config.Config{JWTAlgorithm: "RS256", JWTPrivateKeyFile: privatePath}); err == nil {
+		t.Error("LoadKeySet aceptó una clave EC para RS256")
+	}
+}
+
+func TestKeySetRejectsInvalidTokens(t *testing.T) {
+	rsaKey := newRSAKey(t)
+	privatePath, publicPath := writeKeyFiles(t, "jwt", rsaKey)
+	keys, err := LoadKeySet(&config.Config{JWTAlgorithm: "RS256", JWTPrivateKeyFile: privatePath})
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+	kid := keys.signing.id
+
+	publicPEM, err := os.ReadFile(publicPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ataque de confusión de algoritmo: HS256 con la clave pública como secreto
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	confused.Header["kid"] = kid
+	confusedToken, err := confused.SignedString(publicPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Firmado con otra clave RSA pero con el kid de la clave activa
+	forged := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims())
+	forged.Header["kid"] = kid
+	forgedToken, err := forged.SignedString(newRSAKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unknown := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims())
+	unknown.Header["kid"] = "desconocido"
+	unknownToken, err := unknown.SignedString(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noKid, err := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims()).SignedString(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "HS256 firmado con la clave pública", token: confusedToken},
+		{name: "firma de otra clave", token: forgedToken},
+		{name: "kid desconocido", token: unknownToken},
+		{name: "sin kid", token: noKid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := parseWithKeySet(keys, tt.token); err == nil {
+				t.Error("se aceptó un token inválido")
+			}
+		})
+	}
+}
+
+func TestKeySetVerifiesRotatedKey(t *testing.T) {
+	oldKey := newRSAKey(t)
+	oldPrivate, oldPublic := writeKeyFiles(t, "anterior", oldKey)
+	oldKeys, err := LoadKeySet(&config.Config{JWTAlgorithm: "RS256", JWTPrivateKeyFile: oldPrivate})
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+	oldToken, err := oldKeys.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	newPrivate, _ := writeKeyFiles(t, "actual", newECKey(t))
+	cfg := &config.Config{JWTAlgorithm: "ES256", JWTPrivateKeyFile: newPrivate}
+
+	keys, err := LoadKeySet(cfg)
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+	if err := parseWithKeySet(keys, oldToken); err == nil {
+		t.Fatal("sin JWT_VERIFY_KEY_FILES se aceptó un token de la clave anterior")
+	}
+
+	cfg.JWTVerifyKeyFiles = []string{oldPublic}
+	keys, err = LoadKeySet(cfg)
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+	if err := parseWithKeySet(keys, oldToken); err != nil {
+		t.Errorf("el token de la clave anterior no verifica tras la rotación: %v", err)
+	}
+
+	newToken, err := keys.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if err := parseWithKeySet(keys, newToken); err != nil {
+		t.Errorf("el token de la clave nueva no verifica: %v", err)
+	}
+	// La clave anterior solo verifica: los tokens nuevos no la usan
+	if err := parseWithKeySet(oldKeys, newToken); err == nil {
+		t.Error("la clave anterior verificó un token de la clave nueva")
+	}
+}
+
+func TestThumbprintRFC7638(t *testing.T) {
+	// Ejemplo de la sección 3.1 de RFC 7638
+	n, err := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+
+	got, err := thumbprint(key)
+	if err != nil {
+		t.Fatalf("thumbprint: %v", err)
+	}
+	if want := "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"; got != want {
+		t.Errorf("thumbprint() = %s, se esperaba %s", got, want)
+	}
+}
+
+func TestKeySetJWKS(t *testing.T) {
+	t.Run("HS256 no publica claves", func(t *testing.T) {
+		keys, err := LoadKeySet(&config.Config{JWTAlgorithm: "HS256", JWTSecret: strings.Repeat("j", minSecretLength)})
+		if err != nil {
+			t.Fatalf("LoadKeySet: %v", err)
+		}
+		if got := keys.JWKS().Keys; len(got) != 0 {
+			t.Errorf("JWKS() publicó %d claves HMAC", len(got))
+		}
+	})
+
+	t.Run("clave activa y anteriores", func(t *testing.T) {
+		rsaKey := newRSAKey(t)
+		ecKey := newECKey(t)
+		edKey := newEd25519Key(t)
+
+		privatePath, _ := writeKeyFiles(t, "actual", ecKey)
+		_, rsaPublic := writeKeyFiles(t, "rsa", rsaKey)
+		_, edPublic := writeKeyFiles(t, "ed", edKey)
+		keys, err := LoadKeySet(&config.Config{
+			JWTAlgorithm:      "ES256",
+			JWTPrivateKeyFile: privatePath,
+			JWTKeyID:          "actual",
+			JWTVerifyKeyFiles: []string{"rsa-2024=" + rsaPublic, edPublic},
+		})
+		if err != nil {
+			t.Fatalf("LoadKeySet: %v", err)
+		}
+
+		got := keys.JWKS().Keys
+		if len(got) != 3 {
+			t.Fatalf("JWKS() devolvió %d claves, se esperaban 3", len(got))
+		}
+		if got[0].Kid != "actual" {
+			t.Errorf("la primera clave es %q, se esperaba la activa", got[0].Kid)
+		}
+
+		edKid, err := thumbprint(edKey.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+		encode := base64.RawURLEncoding.EncodeToString
+		want := map[string]JWK{
+			"actual": {Kty: "EC", Use: "sig", Alg: "ES256", Kid: "actual", Crv: "P-256",
+				X: encode(ecKey.X.FillBytes(make([]byte, 32))), Y: encode(ecKey.Y.FillBytes(make([]byte, 32)))},
+			"rsa-2024": {Kty: "RSA", Use: "sig", Alg: "RS256", Kid: "rsa-2024",
+				N: encode(rsaKey.N.Bytes()), E: "AQAB"},
+			edKid: {Kty: "OKP", Use: "sig", Alg: "EdDSA", Kid: edKid, Crv: "Ed25519",
+				X: encode(edKey.Public().(ed25519.PublicKey))},
+		}
+		for _, jwk := range got {
+			if jwk != want[jwk.Kid] {
+				t.Errorf("JWKS() kid %q = %+v, se esperaba %+v", jwk.Kid, jwk, want[jwk.Kid])
+			}
+		}
+	})
+}
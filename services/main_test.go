@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gopost-api/config"
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/database/migrations"
+	"github.com/gopost-api/mailer"
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/repositories"
+	"github.com/gopost-api/repositories/memory"
+)
+
+// testMailer guarda los correos enviados o, si fail es true, falla. Si
+// release no es nil, cada envío espera a que se cierre y, como SMTPMailer,
+// falla si entretanto se canceló ctx.
+type testMailer struct {
+	mu      sync.Mutex
+	fail    bool
+	release chan struct{}
+	sent    []mailer.Message
+}
+
+func (m *testMailer) Send(ctx context.Context, msg mailer.Message) error {
+	if m.release != nil {
+		<-m.release
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.fail {
+		return errors.New("servidor de correo caído")
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// testServices son los servicios armados sobre los stores en memoria
+type testServices struct {
+	users         *memory.UserStore
+	posts         *memory.PostStore
+	refreshTokens *memory.RefreshTokenStore
+	revocations   *memory.RevocationStore
+	userTokens    *memory.UserTokenStore
+	mailer        *testMailer
+	tokens        *TokenService
+	userService   *UserService
+	postService   *PostService
+}
+
+// newTestKeySet devuelve las claves HS256 con que firman los tests
+func newTestKeySet(t *testing.T) *KeySet {
+	t.Helper()
+	keys, err := LoadKeySet(&config.Config{JWTAlgorithm: "HS256", JWTSecret: strings.Repeat("j", minSecretLength)})
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+	return keys
+}
+
+// testUserSettings son los ajustes de UserService comunes a los tests
+var testUserSettings = UserSettings{
+	AppURL:               "http://localhost:8080",
+	EmailVerificationTTL: 24 * time.Hour,
+	PasswordResetTTL:     time.Hour,
+	DeletionGrace:        30 * 24 * time.Hour,
+	DeletionMode:         repositories.PostsAnonymize,
+}
+
+func newTestServices(t *testing.T) *testServices {
+	t.Helper()
+	keys := newTestKeySet(t)
+
+	ts := &testServices{
+		users:         memory.NewUserStore(),
+		posts:         memory.NewPostStore(),
+		refreshTokens: memory.NewRefreshTokenStore(),
+		revocations:   memory.NewRevocationStore(),
+		userTokens:    memory.NewUserTokenStore(),
+		mailer:        &testMailer{},
+	}
+	ts.tokens = NewTokenService(ts.users, ts.refreshTokens, ts.revocations, keys, 15*time.Minute, 24*time.Hour)
+	ts.userService = NewUserService(ts.users, ts.tokens, ts.userTokens, newTestSigner(t, strings.Repeat("u", minSecretLength)),
+		memory.NewAccountStore(ts.users, ts.posts, ts.refreshTokens, ts.userTokens), ts.mailer, PasswordPolicy{MinLength: 8, RejectCommon: true},
+		testUserSettings)
+	ts.postService = NewPostService(ts.posts, ts.users, newTestSigner(t, strings.Repeat("c", minSecretLength)), true)
+	return ts
+}
+
+// newSQLUserService arma UserService sobre los repositorios SQL y una base
+// SQLite en memoria con todas las migraciones, para los casos que dependen
+// de las claves foráneas y los borrados en cascada
+func newSQLUserService(t *testing.T) *UserService {
+	t.Helper()
+	driver, dsn, err := database.ParseURL(":memory:")
+	if err != nil {
+		t.Fatalf("ParseURL: %v", err)
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	// Cada conexión a :memory: es una base distinta
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.New(db, driver)
+	if err != nil {
+		t.Fatalf("migrations.New: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	dialect := database.DialectFor(driver)
+	users := repositories.NewUserRepository(db, dialect)
+	tokens := NewTokenService(users, repositories.NewRefreshTokenRepository(db, dialect), repositories.NewRevocationRepository(db, dialect),
+		newTestKeySet(t), 15*time.Minute, 24*time.Hour)
+	return NewUserService(users, tokens, repositories.NewUserTokenRepository(db, dialect), newTestSigner(t, strings.Repeat("u", minSecretLength)),
+		repositories.NewAccountRepository(db, dialect), &testMailer{}, PasswordPolicy{MinLength: 8, RejectCommon: true}, testUserSettings)
+}
+
+// sentMail espera los envíos en segundo plano y devuelve los correos
+// enviados hasta ahora
+func (ts *testServices) sentMail(t *testing.T) []mailer.Message {
+	t.Helper()
+	if err := ts.userService.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	ts.mailer.mu.Lock()
+	defer ts.mailer.mu.Unlock()
+	return append([]mailer.Message(nil), ts.mailer.sent...)
+}
+
+// createTestUser registra un usuario verificado en el store en memoria
+func createTestUser(t *testing.T, ts *testServices, email string) *models.User {
+	t.Helper()
+	user := &models.User{Name: "Ana", Email: email, Password: "x", EmailVerified: true}
+	if err := ts.users.Create(context.Background(), user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return user
+}
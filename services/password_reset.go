@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/mailer"
+	"github.com/gopost-api/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ForgotPassword envía un enlace para restablecer la contraseña. Como
+// ResendVerification, no informa si el email pertenece a una cuenta: el
+// correo se envía en segundo plano y sus errores solo se registran en el
+// log, así la respuesta y su demora son las mismas exista o no.
+func (s *UserService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.repo.FindByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	s.sendDetached(ctx, "el restablecimiento de contraseña", user.Email, func(ctx context.Context) error {
+		return s.sendPasswordReset(ctx, user)
+	})
+	return nil
+}
+
+func (s *UserService) sendPasswordReset(ctx context.Context, user *models.User) error {
+	recent, err := s.userTokens.recentlyIssued(ctx, user.ID, models.TokenPurposePasswordReset, resendInterval)
+	if err != nil || recent {
+		return err
+	}
+
+	ttl := s.settings.PasswordResetTTL
+	token, err := s.userTokens.issue(ctx, user.ID, models.TokenPurposePasswordReset, user.Email, ttl)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(`Hola %s:
+
+Recibimos un pedido para restablecer tu contraseña. Elige una nueva abriendo
+este enlace:
+
+%s
+
+O envía este token a POST /auth/password/reset:
+
+%s
+
+El enlace vence en %s y solo puede usarse una vez. Si no lo pediste, ignora
+este correo: tu contraseña no cambiará.
+`, user.Name, s.appLink("/reset-password", token), token, ttl)
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Restablece tu contraseña de GoPost",
+		Body:    body,
+	})
+}
+
+// ResetPassword canjea el token de restablecimiento, guarda la nueva
+// contraseña y cierra todas las sesiones abiertas del usuario
+func (s *UserService) ResetPassword(ctx context.Context, token, password string) error {
+	// La contraseña se valida antes de canjear el token para que un error
+	// no obligue a pedir otro correo
+	if problem := s.passwords.validate(password); problem != "" {
+		return apperrors.InvalidField("password", problem)
+	}
+
+	stored, err := s.userTokens.consume(ctx, token, models.TokenPurposePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.repo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		return err
+	}
+	// Si el email cambió después de enviar el enlace, el token ya no vale
+	if !strings.EqualFold(user.Email, stored.Email) {
+		return apperrors.Validation("el token es inválido o expiró")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("error al encriptar contraseña: %w", err)
+	}
+	if err := s.repo.UpdatePassword(ctx, user.ID, string(hashedPassword)); err != nil {
+		return err
+	}
+
+	return s.tokens.LogoutAll(ctx, user.ID)
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopost-api/models"
+)
+
+func TestForgotPasswordHidesAccountExistence(t *testing.T) {
+	tests := []struct {
+		name       string
+		failMailer bool
+		wantSent   int
+	}{
+		{name: "correo enviado", wantSent: 1},
+		{name: "falla el correo", failMailer: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ts := newTestServices(t)
+			if err := ts.users.Create(ctx, &models.User{Name: "Ana", Email: "ana@example.com", Password: "x"}); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			ts.mailer.fail = tt.failMailer
+
+			// La respuesta no distingue cuentas existentes de inexistentes
+			for _, email := range []string{"ana@example.com", "nadie@example.com"} {
+				if err := ts.userService.ForgotPassword(ctx, email); err != nil {
+					t.Errorf("ForgotPassword(%q) = %v, se esperaba nil", email, err)
+				}
+			}
+			if sent := ts.sentMail(t); len(sent) != tt.wantSent {
+				t.Errorf("se enviaron %d correos, se esperaban %d", len(sent), tt.wantSent)
+			}
+		})
+	}
+}
+
+// Un correo lento no debe demorar la respuesta ni fallar porque la
+// petición terminó: así una cuenta existente responde igual que una que no
+func TestForgotPasswordSendsDetachedFromRequest(t *testing.T) {
+	ts := newTestServices(t)
+	if err := ts.users.Create(context.Background(), &models.User{Name: "Ana", Email: "ana@example.com", Password: "x"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	ts.mailer.release = make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	returned := make(chan error, 1)
+	go func() { returned <- ts.userService.ForgotPassword(ctx, "ana@example.com") }()
+
+	select {
+	case err := <-returned:
+		if err != nil {
+			t.Fatalf("ForgotPassword: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ForgotPassword esperó al envío del correo")
+	}
+	// La petición termina antes de que el servidor de correo responda
+	cancel()
+	close(ts.mailer.release)
+
+	if sent := ts.sentMail(t); len(sent) != 1 {
+		t.Errorf("se enviaron %d correos, se esperaba 1", len(sent))
+	}
+}
+
+func TestPasswordResetEmailUsesConfiguredTTL(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	ts.userService.settings.PasswordResetTTL = 90 * time.Minute
+	if err := ts.users.Create(ctx, &models.User{Name: "Ana", Email: "ana@example.com", Password: "x"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := ts.userService.ForgotPassword(ctx, "ana@example.com"); err != nil {
+		t.Fatalf("ForgotPassword: %v", err)
+	}
+	sent := ts.sentMail(t)
+	if len(sent) != 1 {
+		t.Fatalf("se enviaron %d correos, se esperaba 1", len(sent))
+	}
+	if body := sent[0].Body; !strings.Contains(body, "vence en 1h30m0s") {
+		t.Errorf("el correo no indica el vencimiento configurado:\n%s", body)
+	}
+}
@@ -0,0 +1,21 @@
+package services
+
+import "github.com/gopost-api/models"
+
+// Actor es el usuario autenticado que realiza una operación, con el rol
+// que trae su access token
+type Actor struct {
+	UserID uint
+	Role   models.Role
+}
+
+// CanModerate indica si el actor puede actuar sobre contenido ajeno
+func (a Actor) CanModerate() bool {
+	return a.Role == models.RoleModerator || a.Role == models.RoleAdmin
+}
+
+// canModifyPost es la política de edición y eliminación de posts: el
+// autor siempre puede, y moderadores y administradores pueden con todos
+func canModifyPost(actor Actor, post *models.Post) bool {
+	return post.UserID == actor.UserID || actor.CanModerate()
+}
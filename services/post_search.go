@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/repositories"
+)
+
+const (
+	// maxSearchLength limita el texto de búsqueda aceptado
+	maxSearchLength = 200
+	// snippetRadius es cuántos caracteres se muestran a cada lado de la
+	// primera coincidencia en el fragmento
+	snippetRadius = 80
+)
+
+// PostSearchParams son los parámetros de búsqueda que envía el cliente
+type PostSearchParams struct {
+	Query   string
+	Page    int
+	PerPage int
+}
+
+// PostSearchResult es un post encontrado con su relevancia y los textos
+// resaltados. Title y Snippet vienen escapados como HTML y las coincidencias
+// envueltas en <mark>...</mark>.
+type PostSearchResult struct {
+	Post    models.Post `json:"post"`
+	Score   float64     `json:"score"`
+	Title   string      `json:"title_highlight"`
+	Snippet string      `json:"snippet"`
+}
+
+// PostSearchPage es una página de resultados de búsqueda
+type PostSearchPage struct {
+	Query   string
+	Results []PostSearchResult
+	Page    int
+	PerPage int
+	Total   int
+}
+
+// Search busca posts por título y contenido, ordenados por relevancia
+func (s *PostService) Search(ctx context.Context, params PostSearchParams) (*PostSearchPage, error) {
+	query := strings.TrimSpace(params.Query)
+	if query == "" {
+		return nil, apperrors.Validation("el parámetro q es requerido")
+	}
+	if utf8.RuneCountInString(query) > maxSearchLength {
+		return nil, apperrors.Validation(fmt.Sprintf("la búsqueda no puede superar los %d caracteres", maxSearchLength))
+	}
+
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.PerPage == 0 {
+		params.PerPage = repositories.DefaultPerPage
+	}
+	if params.Page < 1 {
+		return nil, apperrors.Validation("page debe ser mayor o igual a 1")
+	}
+	if params.PerPage < 1 || params.PerPage > repositories.MaxPerPage {
+		return nil, apperrors.Validation(fmt.Sprintf("per_page debe estar entre 1 y %d", repositories.MaxPerPage))
+	}
+
+	hits, total, err := s.repo.Search(ctx, repositories.PostSearchQuery{
+		Text:   query,
+		Limit:  params.PerPage,
+		Offset: (params.Page - 1) * params.PerPage,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	terms := repositories.SearchTerms(query)
+	results := make([]PostSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, PostSearchResult{
+			Post:    hit.Post,
+			Score:   hit.Score,
+			Title:   highlight(hit.Post.Title, terms),
+			Snippet: highlight(snippet(hit.Post.Content, terms), terms),
+		})
+	}
+
+	return &PostSearchPage{
+		Query:   query,
+		Results: results,
+		Page:    params.Page,
+		PerPage: params.PerPage,
+		Total:   total,
+	}, nil
+}
+
+// snippet recorta el texto alrededor de la primera coincidencia de alguna
+// palabra, agregando "…" donde se cortó
+func snippet(text string, terms []string) string {
+	runes := []rune(text)
+	if len(runes) <= 2*snippetRadius {
+		return text
+	}
+
+	// Si alguna letra cambia de longitud al pasar a minúsculas, las
+	// posiciones no se corresponden y se recorta desde el principio
+	lower := []rune(strings.ToLower(text))
+	first := -1
+	if len(lower) == len(runes) {
+		for _, term := range terms {
+			if i := indexRunes(lower, []rune(term)); i >= 0 && (first < 0 || i < first) {
+				first = i
+			}
+		}
+	}
+	if first < 0 {
+		first = 0
+	}
+
+	start := first - snippetRadius
+	if start < 0 {
+		start = 0
+	}
+	end := start + 2*snippetRadius
+	if end > len(runes) {
+		end = len(runes)
+		start = end - 2*snippetRadius
+	}
+
+	result := string(runes[start:end])
+	if start > 0 {
+		result = "…" + result
+	}
+	if end < len(runes) {
+		result += "…"
+	}
+	return result
+}
+
+// highlight escapa el texto como HTML y envuelve cada coincidencia, sin
+// distinguir mayúsculas, en <mark>...</mark>
+func highlight(text string, terms []string) string {
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// Algunas letras cambian de longitud al pasar a minúsculas: sin
+		// correspondencia exacta de posiciones no se resalta
+		return html.EscapeString(text)
+	}
+
+	marked := make([]bool, len(runes))
+	for _, term := range terms {
+		needle := []rune(term)
+		for from := 0; from < len(lower); {
+			i := indexRunes(lower[from:], needle)
+			if i < 0 {
+				break
+			}
+			for j := from + i; j < from+i+len(needle); j++ {
+				marked[j] = true
+			}
+			from += i + len(needle)
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && marked[j] == marked[i] {
+			j++
+		}
+		segment := html.EscapeString(string(runes[i:j]))
+		if marked[i] {
+			segment = "<mark>" + segment + "</mark>"
+		}
+		b.WriteString(segment)
+		i = j
+	}
+	return b.String()
+}
+
+// indexRunes es strings.Index sobre runas, para trabajar con posiciones de caracteres
+func indexRunes(haystack, needle []rune) int {
+	if len(needle) == 0 {
+		return -1
+	}
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		match := true
+		for j := range needle {
+			if haystack[i+j] != needle[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
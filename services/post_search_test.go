@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gopost-api/apperrors"
+)
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("a", 100) + " objetivo " + strings.Repeat("b", 100)
+
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		want  string
+	}{
+		{name: "texto corto sin recortar", text: "Hola mundo", terms: []string{"mundo"}, want: "Hola mundo"},
+		{name: "coincidencia al medio", text: long, terms: []string{"objetivo"},
+			want: "…" + strings.Repeat("a", 79) + " objetivo " + strings.Repeat("b", 71) + "…"},
+		{name: "coincidencia al principio", text: "objetivo " + strings.Repeat("b", 200), terms: []string{"objetivo"},
+			want: "objetivo " + strings.Repeat("b", 151) + "…"},
+		{name: "coincidencia al final", text: strings.Repeat("a", 200) + " objetivo", terms: []string{"objetivo"},
+			want: "…" + strings.Repeat("a", 151) + " objetivo"},
+		{name: "sin coincidencias", text: long, terms: []string{"nada"}, want: long[:160] + "…"},
+		{name: "la primera de varias palabras", text: long, terms: []string{"bbb", "objetivo"},
+			want: "…" + strings.Repeat("a", 79) + " objetivo " + strings.Repeat("b", 71) + "…"},
+		{name: "cuenta caracteres y no bytes", text: strings.Repeat("ñ", 100) + " Álvaro " + strings.Repeat("é", 100), terms: []string{"álvaro"},
+			want: "…" + strings.Repeat("ñ", 79) + " Álvaro " + strings.Repeat("é", 73) + "…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snippet(tt.text, tt.terms); got != tt.want {
+				t.Errorf("snippet() = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		want  string
+	}{
+		{name: "sin distinguir mayúsculas", text: "Go es GENIAL", terms: []string{"go", "genial"}, want: "<mark>Go</mark> es <mark>GENIAL</mark>"},
+		{name: "todas las apariciones", text: "go go", terms: []string{"go"}, want: "<mark>go</mark> <mark>go</mark>"},
+		{name: "escapa el HTML", text: `<script>alert("go")</script>`, terms: []string{"go"},
+			want: "&lt;script&gt;alert(&#34;<mark>go</mark>&#34;)&lt;/script&gt;"},
+		{name: "escapa dentro de la marca", text: "a<b", terms: []string{"a<b"}, want: "<mark>a&lt;b</mark>"},
+		{name: "palabras contiguas en una sola marca", text: "gopher", terms: []string{"go", "pher"}, want: "<mark>gopher</mark>"},
+		{name: "letras no ASCII", text: "Hola ÁLVARO", terms: []string{"álvaro"}, want: "Hola <mark>ÁLVARO</mark>"},
+		{name: "sin coincidencias", text: "Hola & chau", terms: []string{"nada"}, want: "Hola &amp; chau"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlight(tt.text, tt.terms); got != tt.want {
+				t.Errorf("highlight() = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchPosts(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	user := createTestUser(t, ts, "ana@example.com")
+	for _, p := range []struct{ title, content string }{
+		{"Viaje con Álvaro", "Un recorrido por el sur"},
+		{"Recetas", "La receta favorita de ÁLVARO"},
+		{"Otro tema", "Nada que ver"},
+	} {
+		if _, err := ts.postService.CreatePost(ctx, user.ID, p.title, p.content); err != nil {
+			t.Fatalf("CreatePost: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		wantTotal int
+		wantErr   error
+	}{
+		{name: "palabra no ASCII en minúsculas", query: "álvaro", wantTotal: 2},
+		{name: "palabra no ASCII en mayúsculas", query: "ÁLVARO", wantTotal: 2},
+		{name: "todas las palabras", query: "álvaro sur", wantTotal: 1},
+		{name: "sin resultados", query: "inexistente"},
+		{name: "búsqueda vacía", query: "   ", wantErr: apperrors.ErrValidation},
+		{name: "búsqueda demasiado larga", query: strings.Repeat("a", maxSearchLength+1), wantErr: apperrors.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := ts.postService.Search(ctx, PostSearchParams{Query: tt.query})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Search() error = %v, se esperaba %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if page.Total != tt.wantTotal || len(page.Results) != tt.wantTotal {
+				t.Fatalf("Search() = %d resultados (total %d), se esperaban %d", len(page.Results), page.Total, tt.wantTotal)
+			}
+			// La coincidencia en el título vale doble
+			if tt.wantTotal == 2 && page.Results[0].Title != "Viaje con <mark>Álvaro</mark>" {
+				t.Errorf("primer resultado = %q, se esperaba el de la coincidencia en el título", page.Results[0].Title)
+			}
+		})
+	}
+}
@@ -3,25 +3,43 @@ package services
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/gopost-api/apperrors"
 	"github.com/gopost-api/models"
 	"github.com/gopost-api/repositories"
 )
 
 type PostService struct {
-	repo *repositories.PostRepository
+	repo    repositories.PostStore
+	users   repositories.UserStore
+	cursors *Signer
+	// requireVerifiedEmail impide publicar a quien no verificó su email
+	requireVerifiedEmail bool
 }
 
-func NewPostService(repo *repositories.PostRepository) *PostService {
-	return &PostService{repo: repo}
+// NewPostService crea el servicio; cursors firma los cursores del feed y
+// requireVerifiedEmail exige el email verificado para publicar
+func NewPostService(repo repositories.PostStore, users repositories.UserStore, cursors *Signer, requireVerifiedEmail bool) *PostService {
+	return &PostService{repo: repo, users: users, cursors: cursors, requireVerifiedEmail: requireVerifiedEmail}
 }
 
 func (s *PostService) CreatePost(ctx context.Context, userID uint, title, content string) (*models.Post, error) {
+	if s.requireVerifiedEmail {
+		user, err := s.users.FindByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !user.EmailVerified {
+			return nil, apperrors.Forbidden("debes verificar tu email antes de publicar")
+		}
+	}
+
 	if title == "" {
-		return nil, fmt.Errorf("el título es requerido")
+		return nil, apperrors.Validation("el título es requerido")
 	}
 	if content == "" {
-		return nil, fmt.Errorf("el contenido es requerido")
+		return nil, apperrors.Validation("el contenido es requerido")
 	}
 
 	post := &models.Post{
@@ -37,33 +55,147 @@ func (s *PostService) CreatePost(ctx context.Context, userID uint, title, conten
 	return post, nil
 }
 
-func (s *PostService) GetAllPosts(ctx context.Context) ([]models.Post, error) {
-	return s.repo.FindAll(ctx)
+// PostListParams son los parámetros de un listado de posts tal como los
+// pide el cliente: página, tamaño de página, orden y filtros
+type PostListParams struct {
+	Page          int
+	PerPage       int
+	Sort          string
+	UserID        uint
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
 }
 
-func (s *PostService) GetPostByID(ctx context.Context, id uint) (*models.Post, error) {
-	return s.repo.FindByID(ctx, id)
+// PostPage es una página de posts junto con los datos para paginar
+type PostPage struct {
+	Posts   []models.Post
+	Page    int
+	PerPage int
+	Total   int
+	Sort    string
+}
+
+// ListPosts valida los parámetros y devuelve la página pedida
+func (s *PostService) ListPosts(ctx context.Context, params PostListParams) (*PostPage, error) {
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.PerPage == 0 {
+		params.PerPage = repositories.DefaultPerPage
+	}
+	if params.Page < 1 {
+		return nil, apperrors.Validation("page debe ser mayor o igual a 1")
+	}
+	if params.PerPage < 1 || params.PerPage > repositories.MaxPerPage {
+		return nil, apperrors.Validation(fmt.Sprintf("per_page debe estar entre 1 y %d", repositories.MaxPerPage))
+	}
+
+	sort, err := repositories.ParsePostSort(params.Sort)
+	if err != nil {
+		return nil, apperrors.Validation(err.Error())
+	}
+
+	posts, total, err := s.repo.List(ctx, repositories.PostQuery{
+		UserID:        params.UserID,
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+		Sort:          sort,
+		Limit:         params.PerPage,
+		Offset:        (params.Page - 1) * params.PerPage,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostPage{
+		Posts:   posts,
+		Page:    params.Page,
+		PerPage: params.PerPage,
+		Total:   total,
+		Sort:    sort.String(),
+	}, nil
+}
+
+// PostFeedParams son los parámetros del feed por cursor: el cursor opaco
+// devuelto en la página anterior (vacío para la primera) y el tamaño
+type PostFeedParams struct {
+	Cursor string
+	Limit  int
+	UserID uint
+}
+
+// PostFeed es una página del feed por cursor
+type PostFeed struct {
+	Posts      []models.Post
+	Limit      int
+	NextCursor string
+	HasMore    bool
 }
 
-func (s *PostService) GetPostsByUserID(ctx context.Context, userID uint) ([]models.Post, error) {
-	return s.repo.FindByUserID(ctx, userID)
+// FeedPosts devuelve la página del feed que sigue al cursor. Es estable ante
+// posts nuevos: un post creado mientras se pagina no se repite ni desplaza
+// a los demás.
+func (s *PostService) FeedPosts(ctx context.Context, params PostFeedParams) (*PostFeed, error) {
+	if params.Limit == 0 {
+		params.Limit = repositories.DefaultPerPage
+	}
+	if params.Limit < 1 || params.Limit > repositories.MaxPerPage {
+		return nil, apperrors.Validation(fmt.Sprintf("limit debe estar entre 1 y %d", repositories.MaxPerPage))
+	}
+
+	var after *repositories.PostKey
+	if params.Cursor != "" {
+		key, err := decodeCursor(s.cursors, params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = key
+	}
+
+	// Se pide un post de más para saber si hay otra página
+	posts, err := s.repo.Feed(ctx, repositories.PostFeedQuery{
+		UserID: params.UserID,
+		After:  after,
+		Limit:  params.Limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &PostFeed{Posts: posts, Limit: params.Limit}
+	if len(posts) > params.Limit {
+		feed.Posts = posts[:params.Limit]
+		feed.HasMore = true
+
+		last := feed.Posts[len(feed.Posts)-1]
+		key := repositories.PostKey{CreatedAt: last.CreatedAt, ID: last.ID}
+		if feed.NextCursor, err = encodeCursor(s.cursors, key); err != nil {
+			return nil, err
+		}
+	}
+
+	return feed, nil
+}
+
+func (s *PostService) GetPostByID(ctx context.Context, id uint) (*models.Post, error) {
+	return s.repo.FindByID(ctx, id)
 }
 
-func (s *PostService) UpdatePost(ctx context.Context, postID, userID uint, title, content string) (*models.Post, error) {
+func (s *PostService) UpdatePost(ctx context.Context, actor Actor, postID uint, title, content string) (*models.Post, error) {
 	post, err := s.repo.FindByID(ctx, postID)
 	if err != nil {
 		return nil, err
 	}
 
-	if post.UserID != userID {
-		return nil, fmt.Errorf("no tienes permiso para actualizar este post")
+	if !canModifyPost(actor, post) {
+		return nil, apperrors.Forbidden("no tienes permiso para actualizar este post")
 	}
 
 	if title == "" {
-		return nil, fmt.Errorf("el título es requerido")
+		return nil, apperrors.Validation("el título es requerido")
 	}
 	if content == "" {
-		return nil, fmt.Errorf("el contenido es requerido")
+		return nil, apperrors.Validation("el contenido es requerido")
 	}
 
 	post.Title = title
@@ -76,14 +208,14 @@ func (s *PostService) UpdatePost(ctx context.Context, postID, userID uint, title
 	return post, nil
 }
 
-func (s *PostService) DeletePost(ctx context.Context, postID, userID uint) error {
+func (s *PostService) DeletePost(ctx context.Context, actor Actor, postID uint) error {
 	post, err := s.repo.FindByID(ctx, postID)
 	if err != nil {
 		return err
 	}
 
-	if post.UserID != userID {
-		return fmt.Errorf("no tienes permiso para eliminar este post")
+	if !canModifyPost(actor, post) {
+		return apperrors.Forbidden("no tienes permiso para eliminar este post")
 	}
 
 	return s.repo.Delete(ctx, postID)
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/repositories"
+)
+
+// createTestPosts publica un post por título y devuelve sus IDs en orden
+// de creación. Se crean en el mismo segundo, así que el orden por fecha
+// se desempata por ID.
+func createTestPosts(t *testing.T, ts *testServices, userID uint, titles ...string) []uint {
+	t.Helper()
+	ids := make([]uint, 0, len(titles))
+	for _, title := range titles {
+		post, err := ts.postService.CreatePost(context.Background(), userID, title, "Contenido de "+title)
+		if err != nil {
+			t.Fatalf("CreatePost: %v", err)
+		}
+		ids = append(ids, post.ID)
+	}
+	return ids
+}
+
+func postIDs(posts []models.Post) []uint {
+	ids := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+	return ids
+}
+
+func TestCreatePostEmailVerification(t *testing.T) {
+	tests := []struct {
+		name     string
+		require  bool
+		verified bool
+		wantErr  error
+	}{
+		{name: "exigida y verificado", require: true, verified: true},
+		{name: "exigida y sin verificar", require: true, verified: false, wantErr: apperrors.ErrForbidden},
+		{name: "no exigida", require: false, verified: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ts := newTestServices(t)
+			posts := NewPostService(ts.posts, ts.users, ts.postService.cursors, tt.require)
+
+			user := &models.User{Name: "Ana", Email: "ana@example.com", Password: "x", EmailVerified: tt.verified}
+			if err := ts.users.Create(ctx, user); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+
+			_, err := posts.CreatePost(ctx, user.ID, "Título", "Contenido")
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("CreatePost: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreatePost() error = %v, se esperaba %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListPosts(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	ana := createTestUser(t, ts, "ana@example.com")
+	luis := createTestUser(t, ts, "luis@example.com")
+	// IDs 1, 2 y 3 de Ana; 4 de Luis
+	createTestPosts(t, ts, ana.ID, "beta", "alfa", "gamma")
+	createTestPosts(t, ts, luis.ID, "delta")
+
+	tests := []struct {
+		name      string
+		params    PostListParams
+		wantIDs   []uint
+		wantTotal int
+		wantSort  string
+		wantErr   error
+	}{
+		{name: "por defecto, más recientes primero", params: PostListParams{}, wantIDs: []uint{4, 3, 2, 1}, wantTotal: 4, wantSort: "-created_at"},
+		{name: "más antiguos primero", params: PostListParams{Sort: "created_at"}, wantIDs: []uint{1, 2, 3, 4}, wantTotal: 4, wantSort: "created_at"},
+		{name: "por título", params: PostListParams{Sort: "title"}, wantIDs: []uint{2, 1, 4, 3}, wantTotal: 4, wantSort: "title"},
+		{name: "por título descendente", params: PostListParams{Sort: "-title"}, wantIDs: []uint{3, 4, 1, 2}, wantTotal: 4, wantSort: "-title"},
+		{name: "segunda página", params: PostListParams{Page: 2, PerPage: 3}, wantIDs: []uint{1}, wantTotal: 4, wantSort: "-created_at"},
+		{name: "página fuera de rango", params: PostListParams{Page: 5, PerPage: 3}, wantIDs: []uint{}, wantTotal: 4, wantSort: "-created_at"},
+		{name: "filtrado por autor", params: PostListParams{UserID: ana.ID}, wantIDs: []uint{3, 2, 1}, wantTotal: 3, wantSort: "-created_at"},
+		{name: "campo de orden inválido", params: PostListParams{Sort: "password"}, wantErr: apperrors.ErrValidation},
+		{name: "página negativa", params: PostListParams{Page: -1}, wantErr: apperrors.ErrValidation},
+		{name: "per_page excesivo", params: PostListParams{PerPage: repositories.MaxPerPage + 1}, wantErr: apperrors.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := ts.postService.ListPosts(ctx, tt.params)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ListPosts() error = %v, se esperaba %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListPosts: %v", err)
+			}
+			if got := postIDs(page.Posts); !slices.Equal(got, tt.wantIDs) {
+				t.Errorf("IDs = %v, se esperaba %v", got, tt.wantIDs)
+			}
+			if page.Total != tt.wantTotal || page.Sort != tt.wantSort {
+				t.Errorf("total = %d, sort = %q; se esperaba %d y %q", page.Total, page.Sort, tt.wantTotal, tt.wantSort)
+			}
+		})
+	}
+}
+
+func TestFeedPostsKeysetPagination(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	user := createTestUser(t, ts, "ana@example.com")
+	createTestPosts(t, ts, user.ID, "uno", "dos", "tres", "cuatro", "cinco")
+
+	pages := []struct {
+		wantIDs  []uint
+		wantMore bool
+	}{
+		{wantIDs: []uint{5, 4}, wantMore: true},
+		{wantIDs: []uint{3, 2}, wantMore: true},
+		{wantIDs: []uint{1}, wantMore: false},
+	}
+
+	cursor := ""
+	for i, want := range pages {
+		feed, err := ts.postService.FeedPosts(ctx, PostFeedParams{Cursor: cursor, Limit: 2})
+		if err != nil {
+			t.Fatalf("página %d: FeedPosts: %v", i+1, err)
+		}
+		if got := postIDs(feed.Posts); !slices.Equal(got, want.wantIDs) {
+			t.Errorf("página %d: IDs = %v, se esperaba %v", i+1, got, want.wantIDs)
+		}
+		if feed.HasMore != want.wantMore || (feed.NextCursor != "") != want.wantMore {
+			t.Errorf("página %d: HasMore = %t, NextCursor = %q", i+1, feed.HasMore, feed.NextCursor)
+		}
+
+		// Un post publicado mientras se pagina no se repite ni desplaza
+		// a los que faltan
+		if i == 0 {
+			createTestPosts(t, ts, user.ID, "nuevo")
+		}
+		cursor = feed.NextCursor
+	}
+}
+
+func TestFeedPostsValidation(t *testing.T) {
+	ts := newTestServices(t)
+
+	tests := []struct {
+		name   string
+		params PostFeedParams
+	}{
+		{name: "limit negativo", params: PostFeedParams{Limit: -1}},
+		{name: "limit excesivo", params: PostFeedParams{Limit: repositories.MaxPerPage + 1}},
+		{name: "cursor alterado", params: PostFeedParams{Cursor: "eyJpZCI6MX0.firma"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ts.postService.FeedPosts(context.Background(), tt.params); !errors.Is(err, apperrors.ErrValidation) {
+				t.Errorf("FeedPosts() error = %v, se esperaba un error de validación", err)
+			}
+		})
+	}
+}
+
+func TestPostServiceErrors(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	author := createTestUser(t, ts, "ana@example.com")
+	other := createTestUser(t, ts, "luis@example.com")
+	postID := createTestPosts(t, ts, author.ID, "propio")[0]
+
+	stranger := Actor{UserID: other.ID, Role: models.RoleUser}
+	moderator := Actor{UserID: other.ID, Role: models.RoleModerator}
+	owner := Actor{UserID: author.ID, Role: models.RoleUser}
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{name: "obtener inexistente", run: func() error {
+			_, err := ts.postService.GetPostByID(ctx, 99)
+			return err
+		}, wantErr: apperrors.ErrNotFound},
+		{name: "actualizar inexistente", run: func() error {
+			_, err := ts.postService.UpdatePost(ctx, owner, 99, "t", "c")
+			return err
+		}, wantErr: apperrors.ErrNotFound},
+		{name: "eliminar inexistente", run: func() error {
+			return ts.postService.DeletePost(ctx, owner, 99)
+		}, wantErr: apperrors.ErrNotFound},
+		{name: "actualizar post ajeno", run: func() error {
+			_, err := ts.postService.UpdatePost(ctx, stranger, postID, "t", "c")
+			return err
+		}, wantErr: apperrors.ErrForbidden},
+		{name: "eliminar post ajeno", run: func() error {
+			return ts.postService.DeletePost(ctx, stranger, postID)
+		}, wantErr: apperrors.ErrForbidden},
+		{name: "actualizar sin título", run: func() error {
+			_, err := ts.postService.UpdatePost(ctx, owner, postID, "", "c")
+			return err
+		}, wantErr: apperrors.ErrValidation},
+		{name: "un moderador puede editar", run: func() error {
+			_, err := ts.postService.UpdatePost(ctx, moderator, postID, "editado", "c")
+			return err
+		}},
+		{name: "el autor puede eliminar", run: func() error {
+			return ts.postService.DeletePost(ctx, owner, postID)
+		}},
+		{name: "eliminado ya no existe", run: func() error {
+			_, err := ts.postService.GetPostByID(ctx, postID)
+			return err
+		}, wantErr: apperrors.ErrNotFound},
+	}
+
+	// Los casos se ejecutan en orden: los últimos dependen de los anteriores
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, se esperaba %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"slices"
+)
+
+// minSecretLength es el largo mínimo de los secretos HMAC
+const minSecretLength = 32
+
+// Signer firma con HMAC-SHA256 los datos que la API entrega al cliente y
+// luego recibe de vuelta, como los cursores de paginación o los tokens
+// enviados por email. Cada uso tiene su propio secreto, distinto del de
+// los access tokens, para que filtrar uno no comprometa a los demás.
+type Signer struct {
+	key []byte
+}
+
+// NewSigner valida el secreto de la variable de entorno name. Se rechazan
+// los secretos vacíos, cortos, iguales al valor por defecto de JWT_SECRET o
+// repetidos de otro uso: reserved son los secretos ya asignados, como
+// JWT_SECRET.
+func NewSigner(name, secret string, reserved ...string) (*Signer, error) {
+	switch {
+	case secret == "":
+		return nil, fmt.Errorf("%s es obligatorio", name)
+	case len(secret) < minSecretLength:
+		return nil, fmt.Errorf("%s debe tener al menos %d caracteres", name, minSecretLength)
+	case slices.Contains(reserved, secret):
+		return nil, fmt.Errorf("%s debe ser distinto de los demás secretos", name)
+	}
+	return &Signer{key: []byte(secret)}, nil
+}
+
+// sign firma data para el propósito indicado; una firma hecha para un
+// propósito no es válida para otro
+func (s *Signer) sign(purpose, data string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(purpose + ":" + data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify compara en tiempo constante la firma recibida con la esperada
+func (s *Signer) verify(purpose, data, signature string) bool {
+	return hmac.Equal([]byte(signature), []byte(s.sign(purpose, data)))
+}
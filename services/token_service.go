@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/repositories"
+)
+
+// TokenPair es el resultado de un inicio de sesión o de una rotación: un
+// access token JWT de vida corta y un refresh token opaco de vida larga
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// TokenService emite access tokens y rota refresh tokens. Cada refresh
+// token se puede usar una sola vez: al usarlo se entrega uno nuevo de la
+// misma familia, y si un token ya usado vuelve a presentarse se asume que
+// fue robado y se revoca la familia completa.
+type TokenService struct {
+	users         repositories.UserStore
+	refreshTokens repositories.RefreshTokenStore
+	revocations   repositories.RevocationStore
+	keys          *KeySet
+	accessTTL     time.Duration
+	refreshTTL    time.Duration
+}
+
+// NewTokenService crea el servicio; accessTTL y refreshTTL son la vigencia
+// de los access tokens y de los refresh tokens
+func NewTokenService(users repositories.UserStore, refreshTokens repositories.RefreshTokenStore, revocations repositories.RevocationStore, keys *KeySet, accessTTL, refreshTTL time.Duration) *TokenService {
+	return &TokenService{users: users, refreshTokens: refreshTokens, revocations: revocations, keys: keys, accessTTL: accessTTL, refreshTTL: refreshTTL}
+}
+
+// JWKS devuelve las claves públicas con que se verifican los access tokens
+func (s *TokenService) JWKS() JWKSet {
+	return s.keys.JWKS()
+}
+
+// ClaimsKey es la clave con la que AuthMiddleware deja los claims del
+// access token en el server.Context
+const ClaimsKey = "token_claims"
+
+// AccessClaims son los datos de un access token ya verificado
+type AccessClaims struct {
+	UserID uint
+	Role   models.Role
+	// TokenID es el claim jti, único por token
+	TokenID string
+	// SessionID es la familia de refresh tokens del inicio de sesión
+	SessionID string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Verify valida la firma y la expiración del access token y comprueba que
+// no haya sido revocado, ni él ni todos los tokens de su usuario. Un corte
+// de revocación alcanza a los tokens emitidos en su mismo milisegundo o
+// antes.
+func (s *TokenService) Verify(ctx context.Context, tokenString string) (*AccessClaims, error) {
+	token, err := jwt.Parse(tokenString, s.keys.Keyfunc,
+		jwt.WithValidMethods(s.keys.Algorithms()), jwt.WithExpirationRequired(), jwt.WithIssuedAt(),
+	)
+	if err != nil || !token.Valid {
+		return nil, apperrors.Unauthorized("Token inválido o expirado")
+	}
+
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, apperrors.Unauthorized("Claims inválidos")
+	}
+
+	userID, ok := mapClaims["user_id"].(float64)
+	if !ok {
+		return nil, apperrors.Unauthorized("User ID no encontrado en el token")
+	}
+	tokenID, _ := mapClaims["jti"].(string)
+	if tokenID == "" {
+		return nil, apperrors.Unauthorized("Token sin identificador")
+	}
+	sessionID, _ := mapClaims["sid"].(string)
+	// Los tokens emitidos antes de que existieran los roles no traen el claim
+	roleClaim, _ := mapClaims["role"].(string)
+	role := models.Role(roleClaim)
+	if !role.Valid() {
+		role = models.RoleUser
+	}
+	// iat se lee a mano: jwt lo redondearía a segundos y se perderían los
+	// milisegundos con que se compara contra el corte de LogoutAll
+	issuedAt, ok := mapClaims["iat"].(float64)
+	if !ok {
+		return nil, apperrors.Unauthorized("Token sin fecha de emisión")
+	}
+	expiresAt, _ := mapClaims.GetExpirationTime()
+
+	claims := &AccessClaims{
+		UserID:    uint(userID),
+		Role:      role,
+		TokenID:   tokenID,
+		SessionID: sessionID,
+		IssuedAt:  time.UnixMilli(int64(math.Round(issuedAt * 1000))).UTC(),
+		ExpiresAt: expiresAt.UTC(),
+	}
+
+	revoked, err := s.revocations.IsTokenRevoked(ctx, claims.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	cutoff, err := s.revocations.UserTokensRevokedBefore(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked || (!cutoff.IsZero() && !claims.IssuedAt.After(cutoff)) {
+		return nil, apperrors.Unauthorized("Token revocado")
+	}
+
+	return claims, nil
+}
+
+// Logout revoca el access token presentado y los refresh tokens de su
+// sesión; las demás sesiones del usuario siguen activas
+func (s *TokenService) Logout(ctx context.Context, claims *AccessClaims) error {
+	if err := s.revocations.RevokeToken(ctx, claims.TokenID, claims.UserID, claims.ExpiresAt); err != nil {
+		return err
+	}
+	if claims.SessionID == "" {
+		return nil
+	}
+	return s.refreshTokens.RevokeFamily(ctx, claims.SessionID, database.Now())
+}
+
+// LogoutAll revoca todos los access y refresh tokens del usuario emitidos
+// en el milisegundo actual o antes
+func (s *TokenService) LogoutAll(ctx context.Context, userID uint) error {
+	// El corte usa la misma precisión de milisegundos que iat: un token
+	// emitido en el mismo milisegundo queda revocado, uno del milisegundo
+	// siguiente ya no
+	return s.revokeAll(ctx, userID, time.Now().UTC().Truncate(time.Millisecond))
+}
+
+// RestartSessions cierra todas las sesiones del usuario y abre una nueva,
+// para quien hizo un cambio sensible como el de su contraseña
+func (s *TokenService) RestartSessions(ctx context.Context, user *models.User) (*TokenPair, error) {
+	cutoff := time.Now().UTC().Truncate(time.Millisecond)
+	if err := s.revokeAll(ctx, user.ID, cutoff); err != nil {
+		return nil, err
+	}
+	familyID, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	// Un token emitido en el mismo milisegundo que el corte nacería
+	// revocado: el par nuevo se emite en el milisegundo siguiente
+	return s.issuePair(ctx, user, familyID, cutoff.Add(time.Millisecond))
+}
+
+// PurgeExpiredRevocations borra las revocaciones de access tokens que ya
+// expiraron: un token vencido se rechaza igual, revocado o no
+func (s *TokenService) PurgeExpiredRevocations(ctx context.Context) (int64, error) {
+	return s.revocations.DeleteExpired(ctx, database.Now())
+}
+
+func (s *TokenService) revokeAll(ctx context.Context, userID uint, before time.Time) error {
+	if err := s.revocations.RevokeUserTokens(ctx, userID, before); err != nil {
+		return err
+	}
+	return s.refreshTokens.RevokeUser(ctx, userID, before)
+}
+
+// Issue inicia una nueva familia de refresh tokens para el usuario
+func (s *TokenService) Issue(ctx context.Context, user *models.User) (*TokenPair, error) {
+	familyID, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	return s.issuePair(ctx, user, familyID, time.Now().UTC().Truncate(time.Millisecond))
+}
+
+// Refresh canjea un refresh token por un par nuevo de la misma familia
+func (s *TokenService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	stored, err := s.refreshTokens.FindByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.Unauthorized("refresh token inválido")
+		}
+		return nil, err
+	}
+
+	if stored.RevokedAt != nil {
+		return nil, apperrors.Unauthorized("refresh token revocado")
+	}
+	if stored.UsedAt != nil {
+		return nil, s.revokeReused(ctx, stored)
+	}
+
+	now := database.Now()
+	if !now.Before(stored.ExpiresAt) {
+		return nil, apperrors.Unauthorized("refresh token expirado")
+	}
+
+	// Si dos pedidos usan el mismo token a la vez solo uno gana la
+	// rotación; el otro se trata como una reutilización
+	ok, err := s.refreshTokens.MarkUsed(ctx, stored.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, s.revokeReused(ctx, stored)
+	}
+
+	// El usuario se vuelve a leer para que el access token nuevo lleve su
+	// rol actual
+	user, err := s.users.FindByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.Unauthorized("refresh token inválido")
+		}
+		return nil, err
+	}
+
+	pair, err := s.issuePair(ctx, user, stored.FamilyID, time.Now().UTC().Truncate(time.Millisecond))
+	if err != nil {
+		return nil, err
+	}
+
+	// Un pedido concurrente que perdió la rotación pudo revocar la familia
+	// antes de que existiera el token nuevo; en ese caso también se revoca
+	current, err := s.refreshTokens.FindByHash(ctx, stored.TokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if current.RevokedAt != nil {
+		return nil, s.revokeReused(ctx, stored)
+	}
+
+	return pair, nil
+}
+
+// revokeReused revoca la familia de un token presentado por segunda vez
+func (s *TokenService) revokeReused(ctx context.Context, stored *models.RefreshToken) error {
+	if err := s.refreshTokens.RevokeFamily(ctx, stored.FamilyID, database.Now()); err != nil {
+		return err
+	}
+	return apperrors.Unauthorized("refresh token reutilizado: se cerró la sesión")
+}
+
+// issuePair emite un par de la familia indicada; issuedAt es el iat del
+// access token, con precisión de milisegundos
+func (s *TokenService) issuePair(ctx context.Context, user *models.User, familyID string, issuedAt time.Time) (*TokenPair, error) {
+	pair := &TokenPair{
+		AccessExpiresAt:  issuedAt.Add(s.accessTTL).UTC().Truncate(time.Second),
+		RefreshExpiresAt: issuedAt.Add(s.refreshTTL).UTC().Truncate(time.Second),
+	}
+
+	var err error
+	pair.AccessToken, err = s.accessToken(user, familyID, issuedAt, pair.AccessExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("error al generar token: %w", err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("error al generar refresh token: %w", err)
+	}
+	pair.RefreshToken = base64.RawURLEncoding.EncodeToString(secret)
+
+	err = s.refreshTokens.Create(ctx, &models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(pair.RefreshToken),
+		ExpiresAt: pair.RefreshExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+func (s *TokenService) accessToken(user *models.User, sessionID string, issuedAt, expiresAt time.Time) (string, error) {
+	tokenID, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.MapClaims{
+		"jti":     tokenID,
+		"sid":     sessionID,
+		"user_id": user.ID,
+		"role":    user.Role,
+		"iat":     numericDateMillis(issuedAt),
+		"exp":     expiresAt.Unix(),
+	}
+
+	return s.keys.Sign(claims)
+}
+
+// numericDateMillis expresa el instante en segundos con milisegundos; el
+// estándar JWT admite NumericDate con decimales
+func numericDateMillis(t time.Time) float64 {
+	return float64(t.UnixMilli()) / 1000
+}
+
+// hashToken es el valor que se guarda en lugar del refresh token. Los
+// tokens son aleatorios de 256 bits, así que un SHA-256 sin sal basta para
+// que una filtración de la tabla no permita usarlos.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error al generar identificador: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+)
+
+func TestRefreshRotationAndReuse(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	user := createTestUser(t, ts, "ana@example.com")
+
+	first, err := ts.tokens.Issue(ctx, user)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	second, err := ts.tokens.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("Refresh devolvió el mismo refresh token")
+	}
+
+	steps := []struct {
+		name  string
+		token string
+	}{
+		// Presentar de nuevo un token usado revoca la familia...
+		{name: "token ya usado", token: first.RefreshToken},
+		// ...y con ella el token que lo reemplazó
+		{name: "token de la familia revocada", token: second.RefreshToken},
+		{name: "token desconocido", token: "no-existe"},
+	}
+	for _, step := range steps {
+		if _, err := ts.tokens.Refresh(ctx, step.token); !errors.Is(err, apperrors.ErrUnauthorized) {
+			t.Errorf("%s: Refresh() error = %v, se esperaba no autenticado", step.name, err)
+		}
+	}
+}
+
+func TestRefreshKeepsOtherFamilies(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	user := createTestUser(t, ts, "ana@example.com")
+
+	stolen, err := ts.tokens.Issue(ctx, user)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	other, err := ts.tokens.Issue(ctx, user)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	if _, err := ts.tokens.Refresh(ctx, stolen.RefreshToken); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if _, err := ts.tokens.Refresh(ctx, stolen.RefreshToken); err == nil {
+		t.Fatal("la reutilización debería fallar")
+	}
+
+	// La otra sesión del usuario no se ve afectada
+	if _, err := ts.tokens.Refresh(ctx, other.RefreshToken); err != nil {
+		t.Errorf("Refresh de otra familia: %v", err)
+	}
+}
+
+func TestRefreshConcurrentRotation(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	user := createTestUser(t, ts, "ana@example.com")
+
+	pair, err := ts.tokens.Issue(ctx, user)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	const requests = 8
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		rotated []*TokenPair
+	)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			next, err := ts.tokens.Refresh(ctx, pair.RefreshToken)
+			if err != nil {
+				if !errors.Is(err, apperrors.ErrUnauthorized) {
+					t.Errorf("Refresh() error = %v, se esperaba no autenticado", err)
+				}
+				return
+			}
+			mu.Lock()
+			rotated = append(rotated, next)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if len(rotated) > 1 {
+		t.Fatalf("%d pedidos concurrentes ganaron la rotación, se esperaba uno como máximo", len(rotated))
+	}
+	// Los pedidos que perdieron la rotación revocan la familia, incluido el
+	// token que haya entregado el ganador
+	for _, next := range rotated {
+		if _, err := ts.tokens.Refresh(ctx, next.RefreshToken); !errors.Is(err, apperrors.ErrUnauthorized) {
+			t.Errorf("el token del ganador sigue vigente: %v", err)
+		}
+	}
+}
+
+func TestVerifyRevocationCutoffMilliseconds(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	user := createTestUser(t, ts, "ana@example.com")
+
+	pair, err := ts.tokens.Issue(ctx, user)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	claims, err := ts.tokens.Verify(ctx, pair.AccessToken)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims.IssuedAt.Nanosecond()%int(time.Millisecond) != 0 {
+		t.Fatalf("iat = %v, se esperaba precisión de milisegundos", claims.IssuedAt)
+	}
+
+	tests := []struct {
+		name        string
+		cutoff      time.Time
+		wantRevoked bool
+	}{
+		{name: "corte un milisegundo antes de iat", cutoff: claims.IssuedAt.Add(-time.Millisecond)},
+		{name: "corte en el mismo milisegundo", cutoff: claims.IssuedAt, wantRevoked: true},
+		{name: "corte posterior", cutoff: claims.IssuedAt.Add(time.Millisecond), wantRevoked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ts.revocations.RevokeUserTokens(ctx, user.ID, tt.cutoff); err != nil {
+				t.Fatalf("RevokeUserTokens: %v", err)
+			}
+			_, err := ts.tokens.Verify(ctx, pair.AccessToken)
+			if tt.wantRevoked && !errors.Is(err, apperrors.ErrUnauthorized) {
+				t.Errorf("Verify() error = %v, se esperaba token revocado", err)
+			}
+			if !tt.wantRevoked && err != nil {
+				t.Errorf("Verify: %v", err)
+			}
+		})
+	}
+}
+
+func TestLogoutRevokesTokens(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	user := createTestUser(t, ts, "ana@example.com")
+
+	current, err := ts.tokens.Issue(ctx, user)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	other, err := ts.tokens.Issue(ctx, user)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	claims, err := ts.tokens.Verify(ctx, current.AccessToken)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	if err := ts.tokens.Logout(ctx, claims); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if _, err := ts.tokens.Verify(ctx, current.AccessToken); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("el access token cerrado sigue vigente: %v", err)
+	}
+	if _, err := ts.tokens.Refresh(ctx, current.RefreshToken); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("el refresh token de la sesión cerrada sigue vigente: %v", err)
+	}
+	if _, err := ts.tokens.Verify(ctx, other.AccessToken); err != nil {
+		t.Errorf("Logout cerró otra sesión: %v", err)
+	}
+
+	// RestartSessions revoca todo lo anterior y el token nuevo nace vigente
+	// aunque se emita en el mismo segundo que el corte
+	restarted, err := ts.tokens.RestartSessions(ctx, user)
+	if err != nil {
+		t.Fatalf("RestartSessions: %v", err)
+	}
+	if _, err := ts.tokens.Verify(ctx, other.AccessToken); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("el token anterior al corte sigue vigente: %v", err)
+	}
+	if _, err := ts.tokens.Verify(ctx, restarted.AccessToken); err != nil {
+		t.Errorf("el token emitido tras el corte quedó revocado: %v", err)
+	}
+}
+
+func TestIssueUsesConfiguredTTLs(t *testing.T) {
+	ts := newTestServices(t)
+	user := createTestUser(t, ts, "ana@example.com")
+	tokens := NewTokenService(ts.users, ts.refreshTokens, ts.revocations, ts.tokens.keys, time.Minute, time.Hour)
+
+	before := time.Now()
+	pair, err := tokens.Issue(context.Background(), user)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	// Los vencimientos se truncan a segundos
+	checks := []struct {
+		name string
+		got  time.Time
+		ttl  time.Duration
+	}{
+		{name: "access token", got: pair.AccessExpiresAt, ttl: time.Minute},
+		{name: "refresh token", got: pair.RefreshExpiresAt, ttl: time.Hour},
+	}
+	for _, check := range checks {
+		want := before.Add(check.ttl)
+		if diff := check.got.Sub(want); diff < -time.Second || diff > time.Second {
+			t.Errorf("%s vence %v, se esperaba cerca de %v", check.name, check.got, want)
+		}
+	}
+}
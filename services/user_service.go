@@ -2,31 +2,121 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/gopost-api/apperrors"
 	"github.com/gopost-api/config"
+	"github.com/gopost-api/mailer"
 	"github.com/gopost-api/models"
 	"github.com/gopost-api/repositories"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserService struct {
-	repo *repositories.UserRepository
+	repo       repositories.UserStore
+	tokens     *TokenService
+	userTokens userTokens
+	accounts   repositories.AccountStore
+	mailer     mailer.Mailer
+	passwords  PasswordPolicy
+	settings   UserSettings
+	// background cuenta los correos que se envían fuera de la petición
+	background sync.WaitGroup
 }
 
-func NewUserService(repo *repositories.UserRepository) *UserService {
-	return &UserService{repo: repo}
+// UserSettings son los valores de configuración que usa UserService
+type UserSettings struct {
+	// AppURL es la URL base de los enlaces que se envían por email
+	AppURL               string
+	EmailVerificationTTL time.Duration
+	PasswordResetTTL     time.Duration
+	// DeletionGrace es el tiempo hasta que se purga una cuenta eliminada;
+	// 0 la borra de inmediato. DeletionMode indica qué pasa con sus posts.
+	DeletionGrace time.Duration
+	DeletionMode  repositories.PostsOnDelete
+}
+
+// NewUserSettings arma los valores a partir de la configuración
+func NewUserSettings(cfg *config.Config) UserSettings {
+	return UserSettings{
+		AppURL:               cfg.AppURL,
+		EmailVerificationTTL: cfg.EmailVerificationTTL,
+		PasswordResetTTL:     cfg.PasswordResetTTL,
+		DeletionGrace:        cfg.AccountDeletionGrace,
+		DeletionMode:         repositories.PostsOnDelete(cfg.AccountDeletionMode),
+	}
+}
+
+// NewUserService crea el servicio; userTokenSigner firma los tokens que se
+// envían por email (verificación, cambio de email y restablecimiento) y
+// passwords es la política que deben cumplir las contraseñas nuevas
+func NewUserService(repo repositories.UserStore, tokens *TokenService, userTokenStore repositories.UserTokenStore, userTokenSigner *Signer, accounts repositories.AccountStore, m mailer.Mailer, passwords PasswordPolicy, settings UserSettings) *UserService {
+	return &UserService{repo: repo, tokens: tokens, userTokens: userTokens{store: userTokenStore, signer: userTokenSigner}, accounts: accounts, mailer: m, passwords: passwords, settings: settings}
+}
+
+// detachedMailTimeout es el tiempo máximo de un envío en segundo plano
+const detachedMailTimeout = time.Minute
+
+// sendDetached ejecuta send en segundo plano, desligado de la cancelación
+// de la petición y con su propio timeout, y solo registra sus errores. Los
+// endpoints que no deben revelar si una cuenta existe responden así en el
+// mismo tiempo haya o no correo que enviar.
+func (s *UserService) sendDetached(ctx context.Context, what, email string, send func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), detachedMailTimeout)
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		defer cancel()
+		if err := send(ctx); err != nil {
+			log.Printf("Error al enviar %s a %s: %v", what, email, err)
+		}
+	}()
+}
+
+// Wait espera a que terminen los correos enviados en segundo plano, o a
+// que se cancele ctx
+func (s *UserService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (s *UserService) SignUp(ctx context.Context, name, email, password string) (*models.User, error) {
+	var fields apperrors.FieldErrors
+	name = strings.TrimSpace(name)
+	if problem := validateName(name); problem != "" {
+		fields.Add("name", problem)
+	}
+	email, problem := validateEmail(email)
+	if problem != "" {
+		fields.Add("email", problem)
+	}
+	if problem := s.passwords.validate(password); problem != "" {
+		fields.Add("password", problem)
+	}
+	if err := fields.Err(); err != nil {
+		return nil, err
+	}
+
 	exists, err := s.repo.EmailExists(ctx, email)
 	if err != nil {
 		return nil, err
 	}
 	if exists {
-		return nil, fmt.Errorf("el email ya está registrado")
+		return nil, apperrors.Conflict("el email ya está registrado")
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -38,43 +128,96 @@ func (s *UserService) SignUp(ctx context.Context, name, email, password string)
 		Name:     name,
 		Email:    email,
 		Password: string(hashedPassword),
+		Role:     models.RoleUser,
 	}
 
 	if err := s.repo.Create(ctx, user); err != nil {
 		return nil, err
 	}
 
+	// La cuenta ya existe aunque falle el correo: el usuario puede pedir
+	// que se reenvíe
+	if err := s.sendVerification(ctx, user); err != nil {
+		log.Printf("Error al enviar la verificación a %s: %v", user.Email, err)
+	}
+
 	return user, nil
 }
 
-func (s *UserService) Login(ctx context.Context, email, password string) (string, error) {
-	user, err := s.repo.FindByEmail(ctx, email)
+func (s *UserService) Login(ctx context.Context, email, password string) (*TokenPair, error) {
+	user, err := s.repo.FindByEmail(ctx, normalizeEmail(email))
 	if err != nil {
-		return "", fmt.Errorf("credenciales inválidas")
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.Unauthorized("credenciales inválidas")
+		}
+		return nil, err
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", fmt.Errorf("credenciales inválidas")
+		return nil, apperrors.Unauthorized("credenciales inválidas")
 	}
 
-	token, err := s.generateToken(user.ID)
-	if err != nil {
-		return "", fmt.Errorf("error al generar token: %w", err)
+	if err := s.repo.UpdateLastLogin(ctx, user.ID, time.Now()); err != nil {
+		return nil, err
 	}
 
-	return token, nil
+	// Iniciar sesión durante el período de gracia conserva la cuenta
+	if user.DeletionRequestedAt != nil {
+		if err := s.repo.CancelDeletion(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		log.Printf("Eliminación de la cuenta %d cancelada al iniciar sesión", user.ID)
+	}
+
+	return s.tokens.Issue(ctx, user)
+}
+
+// Refresh rota el refresh token y entrega un nuevo access token
+func (s *UserService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	return s.tokens.Refresh(ctx, refreshToken)
+}
+
+// Logout cierra la sesión del token presentado
+func (s *UserService) Logout(ctx context.Context, claims *AccessClaims) error {
+	return s.tokens.Logout(ctx, claims)
+}
+
+// LogoutAll cierra todas las sesiones del usuario
+func (s *UserService) LogoutAll(ctx context.Context, userID uint) error {
+	return s.tokens.LogoutAll(ctx, userID)
 }
 
 func (s *UserService) GetUserByID(ctx context.Context, id uint) (*models.User, error) {
 	return s.repo.FindByID(ctx, id)
 }
 
-func (s *UserService) generateToken(userID uint) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 24 * 7).Unix(),
+// GetUserByEmail busca un usuario por email, normalizado como al
+// registrarse
+func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return s.repo.FindByEmail(ctx, normalizeEmail(email))
+}
+
+// ChangeRole asigna un rol a un usuario. Se revocan sus access y refresh
+// tokens, así ningún token conserva el rol anterior: debe volver a iniciar
+// sesión.
+func (s *UserService) ChangeRole(ctx context.Context, actor Actor, userID uint, role models.Role) (*models.User, error) {
+	if actor.Role != models.RoleAdmin {
+		return nil, apperrors.Forbidden("solo un administrador puede cambiar roles")
+	}
+	if !role.Valid() {
+		return nil, apperrors.Validation("el rol debe ser user, moderator o admin")
+	}
+	// Evita que la API quede sin administradores por error
+	if userID == actor.UserID && role != models.RoleAdmin {
+		return nil, apperrors.Validation("no puedes quitarte el rol de administrador")
+	}
+
+	if err := s.repo.UpdateRole(ctx, userID, role); err != nil {
+		return nil, err
+	}
+	if err := s.tokens.LogoutAll(ctx, userID); err != nil {
+		return nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(config.AppConfig.JWTSecret))
+	return s.repo.FindByID(ctx, userID)
 }
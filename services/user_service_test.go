@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/models"
+)
+
+func TestSignUp(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	if _, err := ts.userService.SignUp(ctx, "Ana", "ana@example.com", "caballo-correcto"); err != nil {
+		t.Fatalf("SignUp: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		email     string
+		password  string
+		wantErr   error
+		wantField string
+	}{
+		{name: "email repetido", email: "ana@example.com", password: "caballo-correcto", wantErr: apperrors.ErrConflict},
+		{name: "email repetido con otro formato", email: "  ANA@Example.com ", password: "caballo-correcto", wantErr: apperrors.ErrConflict},
+		{name: "email inválido", email: "ana@localhost", password: "caballo-correcto", wantErr: apperrors.ErrValidation, wantField: "email"},
+		{name: "contraseña común", email: "luis@example.com", password: "password", wantErr: apperrors.ErrValidation, wantField: "password"},
+		{name: "email nuevo", email: "luis@example.com", password: "caballo-correcto"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := ts.userService.SignUp(ctx, "Usuario", tt.email, tt.password)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("SignUp: %v", err)
+				}
+				if user.Email != normalizeEmail(tt.email) {
+					t.Errorf("email = %q, se esperaba normalizado", user.Email)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SignUp() error = %v, se esperaba %v", err, tt.wantErr)
+			}
+			var appErr *apperrors.Error
+			if tt.wantField != "" && (!errors.As(err, &appErr) || len(appErr.Fields) == 0 || appErr.Fields[0].Field != tt.wantField) {
+				t.Errorf("SignUp() error = %v, se esperaba un error en el campo %q", err, tt.wantField)
+			}
+		})
+	}
+
+	// Cada alta exitosa envía su correo de verificación
+	if len(ts.mailer.sent) != 2 {
+		t.Errorf("se enviaron %d correos, se esperaban 2", len(ts.mailer.sent))
+	}
+}
+
+func TestUserServiceNotFound(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+
+	if _, err := ts.userService.GetUserByID(ctx, 99); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetUserByID() error = %v, se esperaba no encontrado", err)
+	}
+	// En el login un email desconocido no se distingue de una contraseña incorrecta
+	if _, err := ts.userService.Login(ctx, "nadie@example.com", "caballo-correcto"); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("Login() error = %v, se esperaba no autenticado", err)
+	}
+}
+
+// Tras un cambio de rol ningún token conserva el rol anterior: ni el
+// access token ni el refresh token que podría emitir uno nuevo
+func TestChangeRoleRevokesTokens(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	signUpTestUser(t, ts, "ana@example.com")
+	pair, err := ts.userService.Login(ctx, "ana@example.com", "Secreta-123")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	user, err := ts.userService.GetUserByEmail(ctx, " ANA@Example.com ")
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	admin := Actor{UserID: user.ID + 1, Role: models.RoleAdmin}
+	if user, err = ts.userService.ChangeRole(ctx, admin, user.ID, models.RoleModerator); err != nil {
+		t.Fatalf("ChangeRole: %v", err)
+	}
+	if user.Role != models.RoleModerator {
+		t.Errorf("Role = %s, se esperaba %s", user.Role, models.RoleModerator)
+	}
+
+	if _, err := ts.tokens.Verify(ctx, pair.AccessToken); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("Verify() error = %v, se esperaba ErrUnauthorized", err)
+	}
+	if _, err := ts.userService.Refresh(ctx, pair.RefreshToken); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("Refresh() error = %v, se esperaba ErrUnauthorized", err)
+	}
+}
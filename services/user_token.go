@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gopost-api/apperrors"
+	"github.com/gopost-api/database"
+	"github.com/gopost-api/models"
+	"github.com/gopost-api/repositories"
+)
+
+// userTokenPayload es el contenido firmado de un token enviado por email.
+// La firma permite descartar tokens alterados o vencidos sin ir a la base
+// de datos; el hash guardado en user_tokens los hace de un solo uso.
+type userTokenPayload struct {
+	UserID    uint   `json:"u"`
+	Purpose   string `json:"p"`
+	Nonce     string `json:"n"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// userTokens emite y canjea los tokens de un solo uso de un propósito
+type userTokens struct {
+	store  repositories.UserTokenStore
+	signer *Signer
+}
+
+// issue genera un token "payload.firma" para el usuario e invalida los que
+// tenía pendientes con el mismo propósito. email es la dirección a la que
+// se enviará.
+func (t userTokens) issue(ctx context.Context, userID uint, purpose, email string, ttl time.Duration) (string, error) {
+	nonce, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	expiresAt := database.Now().Add(ttl)
+
+	payload, err := json.Marshal(userTokenPayload{UserID: userID, Purpose: purpose, Nonce: nonce, ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	token := encoded + "." + t.signer.sign(purpose, encoded)
+
+	now := database.Now()
+	if err := t.store.InvalidateUser(ctx, userID, purpose, now); err != nil {
+		return "", err
+	}
+
+	err = t.store.Create(ctx, &models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		Email:     email,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// consume valida el token y lo marca como usado. Cualquier falla devuelve
+// el mismo error para no revelar si el token existió.
+func (t userTokens) consume(ctx context.Context, token, purpose string) (*models.UserToken, error) {
+	invalid := apperrors.Validation("el token es inválido o expiró")
+
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !t.signer.verify(purpose, encoded, signature) {
+		return nil, invalid
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalid
+	}
+	var payload userTokenPayload
+	if err := json.Unmarshal(raw, &payload); err != nil || payload.Purpose != purpose {
+		return nil, invalid
+	}
+	now := database.Now()
+	if now.Unix() >= payload.ExpiresAt {
+		return nil, invalid
+	}
+
+	stored, err := t.store.FindByHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	if stored.Purpose != purpose || stored.UsedAt != nil || !now.Before(stored.ExpiresAt) {
+		return nil, invalid
+	}
+
+	// Solo un pedido concurrente puede canjear el token
+	used, err := t.store.MarkUsed(ctx, stored.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, invalid
+	}
+	return stored, nil
+}
+
+// recentlyIssued indica si el usuario recibió un token de ese propósito
+// hace menos de interval, para no reenviar correos en ráfaga
+func (t userTokens) recentlyIssued(ctx context.Context, userID uint, purpose string, interval time.Duration) (bool, error) {
+	last, err := t.store.LastCreatedAt(ctx, userID, purpose)
+	if err != nil {
+		return false, err
+	}
+	return !last.IsZero() && time.Since(last) < interval, nil
+}